@@ -0,0 +1,3 @@
+//go:generate go run generator.go
+
+package api
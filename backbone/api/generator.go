@@ -0,0 +1,109 @@
+//+build ignore
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func run(workdir, name string, args ...string) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = workdir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	fmt.Fprintf(os.Stderr, "↪ %s\n", strings.Join(cmd.Args, " "))
+	if err := cmd.Run(); err != nil {
+		if err, ok := err.(*exec.ExitError); ok {
+			os.Exit(err.ExitCode())
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func download(from, to string, mode os.FileMode) (err error) {
+	if info, err := os.Stat(to); err == nil && info.Mode() == mode {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "⬇ download %s\n", to)
+	res, err := http.Get(from)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := res.Body.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := f.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	_, err = io.Copy(f, res.Body)
+	return err
+}
+
+func main() {
+	// Dependencies
+	run(".", "go", "install", "google.golang.org/protobuf/cmd/protoc-gen-go")
+	run(".", "go", "install", "google.golang.org/grpc/cmd/protoc-gen-go-grpc")
+	run(".", "go", "install", "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-grpc-gateway")
+	run(".", "go", "install", "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2")
+	run(".", "go", "install", "mvdan.cc/gofumpt")
+
+	download(
+		"https://github.com/googleapis/googleapis/raw/master/google/api/annotations.proto",
+		"third_party/google/api/annotations.proto",
+		0644,
+	)
+	download(
+		"https://github.com/googleapis/googleapis/raw/master/google/api/http.proto",
+		"third_party/google/api/http.proto",
+		0644,
+	)
+
+	args := []string{
+		"-I.",
+		"-I../../third_party",
+		"--go_out=paths=source_relative:.",
+		"--go-grpc_out=paths=source_relative:.",
+		"--grpc-gateway_out=paths=source_relative:.",
+		"--openapiv2_out=.",
+	}
+
+	for _, api := range []struct {
+		path       string
+		descriptor string
+	}{
+		{path: "admin/v1alpha1", descriptor: "admin.pb"},
+		{path: "featureflag/v1alpha1", descriptor: "featureflag.pb"},
+		{path: "quota/v1alpha1", descriptor: "quota.pb"},
+	} {
+		path, err := filepath.Abs(api.path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		run(path, "bash", "-c", "rm -f ./*{.pb{,.gw}.go,.swagger.json}")
+
+		run(path, "bash", "-c", fmt.Sprintf("protoc %s --descriptor_set_out=%s *.proto", strings.Join(args, " "), api.descriptor))
+
+		run(path, "gofumpt", "-w", ".")
+	}
+}
@@ -6,37 +6,43 @@ import (
 	"os"
 
 	"github.com/spf13/pflag"
+	"htdvisser.dev/exp/backbone/config"
 	"htdvisser.dev/exp/backbone/server"
+	"htdvisser.dev/exp/backbone/server/admin"
 	"htdvisser.dev/exp/backbone/server/recovery"
 	"htdvisser.dev/exp/backbone/server/reflection"
 	"htdvisser.dev/exp/clicontext"
 	"htdvisser.dev/exp/pflagenv"
 )
 
-var config struct {
-	server server.Config
-}
+var serverConfig server.Config
 
 func init() {
-	pflag.CommandLine.AddFlagSet(config.server.Flags("", nil))
+	pflag.CommandLine.AddFlagSet(serverConfig.Flags("", nil))
 }
 
 func Example() {
 	ctx, exit := clicontext.WithInterruptAndExit(context.Background())
 	defer exit()
 
-	if err := pflagenv.NewParser(pflagenv.Prefixes("backbone_")).ParseEnv(pflag.CommandLine); err != nil {
+	loader := config.NewLoader(pflag.CommandLine, config.WithEnvOptions(pflagenv.Prefixes("backbone_")))
+	if err := loader.Load(os.Args[1:]); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		pflag.Usage()
 		os.Exit(2)
 	}
 
-	pflag.Parse()
+	// Reload dynamic settings (see config.NewValue) on SIGHUP or when the config files change.
+	go loader.Watch(ctx, 0)
 
-	server := server.New(config.server)
+	server := server.New(serverConfig)
 
 	reflection.Register(server)
 	recovery.Register(server)
+	if err := admin.Register(ctx, server, admin.WithFlags(loader)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
 
 	// TODO: Register services here.
 
@@ -0,0 +1,229 @@
+// Package backbonetest provides a test harness for servers that are built with the backbone.
+//
+// The harness runs a server.Server without opening network ports: gRPC servers and
+// stream servers are served on in-memory (bufconn) listeners, packet servers are served
+// on in-memory packet connections, and HTTP servers are served by httptest servers.
+package backbonetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"htdvisser.dev/exp/backbone/server"
+	"htdvisser.dev/exp/backbone/server/http"
+)
+
+const bufferSize = 1 << 20
+
+// StopTimeout is the time that the harness waits for the server to stop during cleanup.
+var StopTimeout = 10 * time.Second
+
+// Harness runs a server.Server for tests.
+type Harness struct {
+	t      testing.TB
+	Server *server.Server
+
+	mu          sync.Mutex
+	listeners   map[string]*bufconn.Listener
+	packetConns *packetNetwork
+	httpServers map[string]*httptest.Server
+	closers     []func() error
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// New instantiates a new server.Server for tests. The server is not started until
+// Start is called, so that the test can register services and servers first.
+// The server is stopped and the clients are closed when the test finishes.
+func New(t testing.TB, opts ...server.Option) *Harness {
+	t.Helper()
+	h := &Harness{
+		t:           t,
+		listeners:   make(map[string]*bufconn.Listener),
+		packetConns: newPacketNetwork(),
+		httpServers: make(map[string]*httptest.Server),
+	}
+	opts = append([]server.Option{
+		server.WithInternalHTTPOptions(http.WithServeMux(stdhttp.NewServeMux())),
+	}, opts...)
+	opts = append(opts,
+		server.WithListenFunc(h.listen),
+		server.WithListenPacketFunc(h.listenPacket),
+	)
+	h.Server = server.New(server.Config{
+		ListenHTTP:         ":0",
+		ListenGRPC:         ":0",
+		ListenInternalHTTP: ":0",
+		ListenInternalGRPC: ":0",
+	}, opts...)
+	t.Cleanup(h.stop)
+	return h
+}
+
+// Start starts the server. Servers that are registered after Start are not served.
+func (h *Harness) Start() {
+	h.t.Helper()
+	if h.done != nil {
+		h.t.Fatal("backbonetest: server already started")
+	}
+	var ctx context.Context
+	ctx, h.cancel = context.WithCancel(context.Background())
+	h.done = make(chan error, 1)
+	go func() {
+		h.done <- h.Server.Run(ctx)
+	}()
+}
+
+func (h *Harness) stop() {
+	h.mu.Lock()
+	closers := h.closers
+	h.closers = nil
+	h.mu.Unlock()
+	for i := len(closers) - 1; i >= 0; i-- {
+		closers[i]()
+	}
+	if h.done == nil {
+		return
+	}
+	h.cancel()
+	select {
+	case err := <-h.done:
+		if err != nil && !errors.Is(err, context.Canceled) {
+			h.t.Errorf("backbonetest: server stopped with error: %v", err)
+		}
+	case <-time.After(StopTimeout):
+		h.t.Errorf("backbonetest: server did not stop within %s", StopTimeout)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, ts := range h.httpServers {
+		ts.Close()
+	}
+	for _, lis := range h.listeners {
+		lis.Close()
+	}
+}
+
+func (h *Harness) addCloser(close func() error) {
+	h.mu.Lock()
+	h.closers = append(h.closers, close)
+	h.mu.Unlock()
+}
+
+func (h *Harness) listener(name string) *bufconn.Listener {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lis, ok := h.listeners[name]
+	if !ok {
+		lis = bufconn.Listen(bufferSize)
+		h.listeners[name] = lis
+	}
+	return lis
+}
+
+func (h *Harness) httpServer(name string) *httptest.Server {
+	var handler stdhttp.Handler
+	switch name {
+	case "HTTP":
+		handler = h.Server.HTTP
+	case "internal HTTP":
+		handler = h.Server.InternalHTTP
+	default:
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ts, ok := h.httpServers[name]
+	if !ok {
+		ts = httptest.NewServer(handler)
+		h.httpServers[name] = ts
+	}
+	return ts
+}
+
+func (h *Harness) listen(name, _, _ string) (net.Listener, error) {
+	if h.httpServer(name) != nil {
+		// HTTP servers are served by the httptest server.
+		return nil, nil
+	}
+	return h.listener(name), nil
+}
+
+func (h *Harness) listenPacket(name, _, _ string) (net.PacketConn, error) {
+	return h.packetConns.listen(name), nil
+}
+
+// DialStream connects to the registered TCP server with the given name.
+// The connection is closed when the test finishes.
+func (h *Harness) DialStream(name string) net.Conn {
+	h.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), StopTimeout)
+	defer cancel()
+	conn, err := h.listener(name).DialContext(ctx)
+	if err != nil {
+		h.t.Fatalf("backbonetest: failed to dial %s server: %v", name, err)
+	}
+	h.addCloser(conn.Close)
+	return conn
+}
+
+// DialPacket returns a connection to the registered UDP server with the given name.
+// The connection is closed when the test finishes.
+func (h *Harness) DialPacket(name string) net.Conn {
+	conn := h.packetConns.dial(name)
+	h.addCloser(conn.Close)
+	return conn
+}
+
+// ClientConn returns a gRPC client connection to the registered gRPC server with the given name.
+// The connection is closed when the test finishes.
+func (h *Harness) ClientConn(name string, opts ...grpc.DialOption) *grpc.ClientConn {
+	h.t.Helper()
+	lis := h.listener(name)
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	}, opts...)
+	conn, err := grpc.Dial(fmt.Sprintf("passthrough:///%s", lis.Addr()), opts...)
+	if err != nil {
+		h.t.Fatalf("backbonetest: failed to dial %s server: %v", name, err)
+	}
+	h.addCloser(conn.Close)
+	return conn
+}
+
+// GRPCConn returns a gRPC client connection to the gRPC server.
+func (h *Harness) GRPCConn(opts ...grpc.DialOption) *grpc.ClientConn {
+	h.t.Helper()
+	return h.ClientConn("gRPC", opts...)
+}
+
+// InternalGRPCConn returns a gRPC client connection to the internal gRPC server.
+func (h *Harness) InternalGRPCConn(opts ...grpc.DialOption) *grpc.ClientConn {
+	h.t.Helper()
+	return h.ClientConn("internal gRPC", opts...)
+}
+
+// HTTP returns the httptest server that serves the HTTP server (including the gRPC-gateway).
+// Use its URL and Client to make requests.
+func (h *Harness) HTTP() *httptest.Server {
+	return h.httpServer("HTTP")
+}
+
+// InternalHTTP returns the httptest server that serves the internal HTTP server.
+func (h *Harness) InternalHTTP() *httptest.Server {
+	return h.httpServer("internal HTTP")
+}
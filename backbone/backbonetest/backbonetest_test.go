@@ -0,0 +1,76 @@
+package backbonetest
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"htdvisser.dev/exp/backbone/server/packet"
+	"htdvisser.dev/exp/backbone/server/stream"
+)
+
+func TestHarness(t *testing.T) {
+	h := New(t)
+	h.Server.HTTP.ServeMux.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	})
+	if err := h.Server.RegisterTCPServer("echo", ":0", stream.NewServer(stream.HandlerFunc(func(_ context.Context, conn net.Conn) error {
+		_, err := io.Copy(conn, conn)
+		return err
+	}))); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Server.RegisterUDPServer("echo", ":0", packet.NewServer(packet.HandlerFunc(func(_ context.Context, pkt []byte, _ net.Addr, reply func([]byte) error) error {
+		return reply(pkt)
+	}))); err != nil {
+		t.Fatal(err)
+	}
+	h.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := healthpb.NewHealthClient(h.GRPCConn()).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("health check failed: %v", err)
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("unexpected health status %s", res.Status)
+	}
+
+	httpRes, err := h.HTTP().Client().Get(h.HTTP().URL + "/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(httpRes.Body)
+	httpRes.Body.Close()
+	if string(body) != "hello" {
+		t.Errorf("unexpected HTTP response %q", body)
+	}
+
+	conn := h.DialStream("echo")
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.WriteString(conn, "ping\n"); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "ping\n" {
+		t.Errorf("unexpected stream response %q (%v)", line, err)
+	}
+
+	pc := h.DialPacket("echo")
+	pc.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := pc.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 16)
+	n, err := pc.Read(buf)
+	if err != nil || string(buf[:n]) != "ping" {
+		t.Errorf("unexpected packet response %q (%v)", buf[:n], err)
+	}
+}
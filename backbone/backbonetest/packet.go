@@ -0,0 +1,192 @@
+package backbonetest
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const packetNetworkName = "memory"
+
+type packetAddr string
+
+func (packetAddr) Network() string  { return packetNetworkName }
+func (a packetAddr) String() string { return string(a) }
+
+type datagram struct {
+	data []byte
+	addr net.Addr
+}
+
+// packetNetwork is an in-memory network for packet connections.
+type packetNetwork struct {
+	mu      sync.Mutex
+	conns   map[string]*packetConn
+	clients int
+}
+
+func newPacketNetwork() *packetNetwork {
+	return &packetNetwork{conns: make(map[string]*packetConn)}
+}
+
+func (n *packetNetwork) newConn(addr string) *packetConn {
+	conn := &packetConn{
+		network: n,
+		addr:    packetAddr(addr),
+		in:      make(chan datagram, 64),
+		closed:  make(chan struct{}),
+	}
+	n.conns[addr] = conn
+	return conn
+}
+
+func (n *packetNetwork) listen(name string) *packetConn {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if conn, ok := n.conns[name]; ok {
+		return conn
+	}
+	return n.newConn(name)
+}
+
+func (n *packetNetwork) dial(name string) net.Conn {
+	server := n.listen(name)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.clients++
+	return &connectedPacketConn{
+		packetConn: n.newConn(fmt.Sprintf("%s client %d", name, n.clients)),
+		remoteAddr: server.addr,
+	}
+}
+
+func (n *packetNetwork) lookup(addr net.Addr) *packetConn {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.conns[addr.String()]
+}
+
+// packetConn is an in-memory net.PacketConn.
+type packetConn struct {
+	network *packetNetwork
+	addr    packetAddr
+	in      chan datagram
+
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	mu            sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func deadlineTimer(deadline time.Time) (<-chan time.Time, func()) {
+	if deadline.IsZero() {
+		return nil, func() {}
+	}
+	timer := time.NewTimer(time.Until(deadline))
+	return timer.C, func() { timer.Stop() }
+}
+
+func (c *packetConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	c.mu.Lock()
+	timeout, stop := deadlineTimer(c.readDeadline)
+	c.mu.Unlock()
+	defer stop()
+	select {
+	case <-c.closed:
+		return 0, nil, net.ErrClosed
+	case <-timeout:
+		return 0, nil, os.ErrDeadlineExceeded
+	case pkt := <-c.in:
+		return copy(b, pkt.data), pkt.addr, nil
+	}
+}
+
+// WriteTo writes a packet to addr. Like UDP, packets to unknown addresses are dropped.
+func (c *packetConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, net.ErrClosed
+	default:
+	}
+	peer := c.network.lookup(addr)
+	if peer == nil {
+		return len(b), nil
+	}
+	c.mu.Lock()
+	timeout, stop := deadlineTimer(c.writeDeadline)
+	c.mu.Unlock()
+	defer stop()
+	pkt := datagram{data: append([]byte(nil), b...), addr: c.addr}
+	select {
+	case <-c.closed:
+		return 0, net.ErrClosed
+	case <-timeout:
+		return 0, os.ErrDeadlineExceeded
+	case <-peer.closed:
+		return len(b), nil
+	case peer.in <- pkt:
+		return len(b), nil
+	}
+}
+
+func (c *packetConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.network.mu.Lock()
+		delete(c.network.conns, c.addr.String())
+		c.network.mu.Unlock()
+	})
+	return nil
+}
+
+func (c *packetConn) LocalAddr() net.Addr { return c.addr }
+
+func (c *packetConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline, c.writeDeadline = t, t
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *packetConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *packetConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.writeDeadline = t
+	c.mu.Unlock()
+	return nil
+}
+
+// connectedPacketConn is a packetConn that only exchanges packets with remoteAddr,
+// similar to a connection that is returned by net.DialUDP.
+type connectedPacketConn struct {
+	*packetConn
+	remoteAddr net.Addr
+}
+
+func (c *connectedPacketConn) Read(b []byte) (int, error) {
+	for {
+		n, addr, err := c.ReadFrom(b)
+		if err != nil {
+			return n, err
+		}
+		if addr.String() == c.remoteAddr.String() {
+			return n, nil
+		}
+	}
+}
+
+func (c *connectedPacketConn) Write(b []byte) (int, error) {
+	return c.WriteTo(b, c.remoteAddr)
+}
+
+func (c *connectedPacketConn) RemoteAddr() net.Addr { return c.remoteAddr }
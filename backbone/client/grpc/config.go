@@ -0,0 +1,197 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"htdvisser.dev/exp/tlsconfig"
+)
+
+// Config is the configuration for gRPC client connections.
+type Config struct {
+	TLS       bool                   `json:"tls,omitempty" yaml:"tls,omitempty"`
+	TLSConfig tlsconfig.ClientConfig `json:"tlsConfig,omitempty" yaml:"tlsConfig,omitempty"`
+
+	LoadBalancingPolicy string `json:"loadBalancingPolicy,omitempty" yaml:"loadBalancingPolicy,omitempty"`
+
+	KeepaliveTime    time.Duration `json:"keepaliveTime,omitempty" yaml:"keepaliveTime,omitempty"`
+	KeepaliveTimeout time.Duration `json:"keepaliveTimeout,omitempty" yaml:"keepaliveTimeout,omitempty"`
+
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	RetryMaxAttempts       int           `json:"retryMaxAttempts,omitempty" yaml:"retryMaxAttempts,omitempty"`
+	RetryInitialBackoff    time.Duration `json:"retryInitialBackoff,omitempty" yaml:"retryInitialBackoff,omitempty"`
+	RetryMaxBackoff        time.Duration `json:"retryMaxBackoff,omitempty" yaml:"retryMaxBackoff,omitempty"`
+	RetryBackoffMultiplier float64       `json:"retryBackoffMultiplier,omitempty" yaml:"retryBackoffMultiplier,omitempty"`
+	RetryableStatusCodes   []string      `json:"retryableStatusCodes,omitempty" yaml:"retryableStatusCodes,omitempty"`
+}
+
+// DefaultConfig returns the default configuration for gRPC client connections.
+func DefaultConfig() *Config {
+	return &Config{
+		TLSConfig: tlsconfig.ClientConfig{
+			ServerCA: tlsconfig.CAConfig{
+				CACert: "", // use system CAs.
+			},
+		},
+		LoadBalancingPolicy:    "round_robin",
+		KeepaliveTime:          time.Minute,
+		KeepaliveTimeout:       20 * time.Second,
+		Timeout:                10 * time.Second,
+		RetryMaxAttempts:       3,
+		RetryInitialBackoff:    100 * time.Millisecond,
+		RetryMaxBackoff:        time.Second,
+		RetryBackoffMultiplier: 2,
+		RetryableStatusCodes:   []string{"UNAVAILABLE"},
+	}
+}
+
+// Flags returns a flagset that can be added to the command line.
+func (c *Config) Flags(prefix string, defaults *Config) *pflag.FlagSet {
+	var flags pflag.FlagSet
+	if defaults == nil {
+		defaults = DefaultConfig()
+	}
+	flags.BoolVar(&c.TLS, prefix+"tls", defaults.TLS, "Use TLS to connect to gRPC servers")
+	flags.AddFlagSet(c.TLSConfig.Flags(prefix+"tls.", &defaults.TLSConfig))
+	flags.StringVar(&c.LoadBalancingPolicy, prefix+"loadBalancingPolicy", defaults.LoadBalancingPolicy, "Load balancing policy (pick_first or round_robin)")
+	flags.DurationVar(&c.KeepaliveTime, prefix+"keepalive.time", defaults.KeepaliveTime, "Interval for keepalive pings (0 to disable)")
+	flags.DurationVar(&c.KeepaliveTimeout, prefix+"keepalive.timeout", defaults.KeepaliveTimeout, "Timeout for keepalive pings")
+	flags.DurationVar(&c.Timeout, prefix+"timeout", defaults.Timeout, "Default deadline for unary calls (0 to disable)")
+	flags.IntVar(&c.RetryMaxAttempts, prefix+"retry.maxAttempts", defaults.RetryMaxAttempts, "Maximum number of attempts for calls (including the original call, 0 to disable retries)")
+	flags.DurationVar(&c.RetryInitialBackoff, prefix+"retry.initialBackoff", defaults.RetryInitialBackoff, "Initial backoff between retries")
+	flags.DurationVar(&c.RetryMaxBackoff, prefix+"retry.maxBackoff", defaults.RetryMaxBackoff, "Maximum backoff between retries")
+	flags.Float64Var(&c.RetryBackoffMultiplier, prefix+"retry.backoffMultiplier", defaults.RetryBackoffMultiplier, "Multiplier for the backoff between retries")
+	flags.StringSliceVar(&c.RetryableStatusCodes, prefix+"retry.statusCodes", defaults.RetryableStatusCodes, "Status codes for which calls are retried")
+	return &flags
+}
+
+type serviceConfigDuration time.Duration
+
+func (d serviceConfigDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%.9fs", time.Duration(d).Seconds()))
+}
+
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig,omitempty"`
+	MethodConfig        []methodConfig        `json:"methodConfig,omitempty"`
+}
+
+type methodConfig struct {
+	Name        []struct{}   `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int                   `json:"maxAttempts"`
+	InitialBackoff       serviceConfigDuration `json:"initialBackoff"`
+	MaxBackoff           serviceConfigDuration `json:"maxBackoff"`
+	BackoffMultiplier    float64               `json:"backoffMultiplier"`
+	RetryableStatusCodes []string              `json:"retryableStatusCodes"`
+}
+
+// ServiceConfig returns the default service config (in JSON) for the configured
+// load balancing policy and retry policy. The retry policy is left out if there
+// is at most one attempt, or if there are no retryable status codes.
+func (c *Config) ServiceConfig() (string, error) {
+	var sc serviceConfig
+	if c.LoadBalancingPolicy != "" {
+		sc.LoadBalancingConfig = []map[string]struct{}{{c.LoadBalancingPolicy: {}}}
+	}
+	if c.RetryMaxAttempts > 1 && len(c.RetryableStatusCodes) > 0 {
+		retryableStatusCodes := make([]string, len(c.RetryableStatusCodes))
+		for i, statusCode := range c.RetryableStatusCodes {
+			var code codes.Code
+			if err := code.UnmarshalJSON([]byte(fmt.Sprintf("%q", strings.ToUpper(statusCode)))); err != nil {
+				return "", fmt.Errorf("invalid retryable status code %q: %w", statusCode, err)
+			}
+			retryableStatusCodes[i] = strings.ToUpper(statusCode)
+		}
+		sc.MethodConfig = []methodConfig{{
+			Name: []struct{}{{}}, // All methods.
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          c.RetryMaxAttempts,
+				InitialBackoff:       serviceConfigDuration(c.RetryInitialBackoff),
+				MaxBackoff:           serviceConfigDuration(c.RetryMaxBackoff),
+				BackoffMultiplier:    c.RetryBackoffMultiplier,
+				RetryableStatusCodes: retryableStatusCodes,
+			},
+		}}
+	}
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// DialOptions returns the DialOptions for the configuration.
+func (c *Config) DialOptions(ctx context.Context) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if c.TLS {
+		tlsConfig, err := c.TLSConfig.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	serviceConfig, err := c.ServiceConfig()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, grpc.WithDefaultServiceConfig(serviceConfig))
+	if c.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    c.KeepaliveTime,
+			Timeout: c.KeepaliveTimeout,
+		}))
+	}
+	unaryInterceptors := []grpc.UnaryClientInterceptor{
+		TracingUnaryClientInterceptor(),
+		MetricsUnaryClientInterceptor(),
+	}
+	if c.Timeout > 0 {
+		unaryInterceptors = append(unaryInterceptors, TimeoutUnaryClientInterceptor(c.Timeout))
+	}
+	opts = append(opts,
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(
+			TracingStreamClientInterceptor(),
+			MetricsStreamClientInterceptor(),
+		),
+	)
+	return opts, nil
+}
+
+// Target returns the target with the "dns" scheme if target doesn't start with the scheme
+// of a registered resolver, so that the client connection balances over all addresses
+// that the name resolves to.
+func Target(target string) string {
+	if scheme, _, ok := strings.Cut(target, ":"); ok && resolver.Get(scheme) != nil {
+		return target
+	}
+	return "dns:///" + target
+}
+
+// DialContext dials target using the configuration. The DialOptions in the context
+// and the given opts are added after the DialOptions of the configuration.
+func (c *Config) DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	configOpts, err := c.DialOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(append(configOpts, DialOptionsFromContext(ctx)...), opts...)
+	return grpc.DialContext(ctx, Target(target), opts...)
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServiceConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.RetryInitialBackoff = 250 * time.Millisecond
+	config.RetryableStatusCodes = []string{"unavailable", "RESOURCE_EXHAUSTED"}
+
+	serviceConfig, err := config.ServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"loadBalancingConfig":[{"round_robin":{}}],"methodConfig":[{"name":[{}],"retryPolicy":{"maxAttempts":3,"initialBackoff":"0.250000000s","maxBackoff":"1.000000000s","backoffMultiplier":2,"retryableStatusCodes":["UNAVAILABLE","RESOURCE_EXHAUSTED"]}}]}`
+	if serviceConfig != expected {
+		t.Errorf("service config was %s, expected %s", serviceConfig, expected)
+	}
+
+	config.RetryableStatusCodes = nil
+	serviceConfig, err = config.ServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+	if serviceConfig != expected {
+		t.Errorf("service config without retryable status codes was %s, expected %s", serviceConfig, expected)
+	}
+
+	config.RetryableStatusCodes = []string{"NOT_A_CODE"}
+	if _, err := config.ServiceConfig(); err == nil {
+		t.Error("expected error for invalid status code")
+	}
+}
+
+func TestTarget(t *testing.T) {
+	for target, expected := range map[string]string{
+		"localhost:9090":          "dns:///localhost:9090",
+		"dns:///localhost:9090":   "dns:///localhost:9090",
+		"passthrough:///10.0.0.1": "passthrough:///10.0.0.1",
+		"unix:///tmp/grpc.sock":   "unix:///tmp/grpc.sock",
+		"dns://8.8.8.8/host:443":  "dns://8.8.8.8/host:443",
+		"passthrough:10.0.0.1":    "passthrough:10.0.0.1",
+	} {
+		if actual := Target(target); actual != expected {
+			t.Errorf("Target(%q) was %q, expected %q", target, actual, expected)
+		}
+	}
+}
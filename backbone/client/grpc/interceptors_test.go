@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer fails the first calls, and records what it received.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	failures      int32
+	calls         atomic.Int32
+	deadline      atomic.Value // time.Time
+	authorization atomic.Value // string
+}
+
+func (s *healthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	call := s.calls.Add(1)
+	if deadline, ok := ctx.Deadline(); ok {
+		s.deadline.Store(deadline)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) > 0 {
+		s.authorization.Store(values[0])
+	}
+	if call <= s.failures {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+// startHealthServer starts a health server on an in-memory listener, and returns
+// a DialOption that connects to it.
+func startHealthServer(t *testing.T, srv *healthServer) grpc.DialOption {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+}
+
+func testConfig() *Config {
+	config := DefaultConfig()
+	config.RetryInitialBackoff = time.Millisecond
+	config.RetryMaxBackoff = time.Millisecond
+	return config
+}
+
+func TestRetry(t *testing.T) {
+	srv := &healthServer{failures: 2}
+	dialer := startHealthServer(t, srv)
+
+	ctx := context.Background()
+	conn, err := testConfig().DialContext(ctx, "passthrough:///bufnet", dialer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("call failed after retries: %v", err)
+	}
+	if calls := srv.calls.Load(); calls != 3 {
+		t.Errorf("server received %d calls, want 3", calls)
+	}
+
+	srv.calls.Store(0)
+	config := testConfig()
+	config.RetryMaxAttempts = 2
+	conn, err = config.DialContext(ctx, "passthrough:///bufnet", dialer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("call returned %v, want Unavailable after 2 attempts", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	srv := &healthServer{}
+	dialer := startHealthServer(t, srv)
+
+	config := testConfig()
+	config.Timeout = time.Minute
+	conn, err := config.DialContext(context.Background(), "passthrough:///bufnet", dialer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	start := time.Now()
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	deadline, _ := srv.deadline.Load().(time.Time)
+	if deadline.Before(start.Add(59*time.Second)) || deadline.After(start.Add(time.Minute+time.Second)) {
+		t.Errorf("server deadline is %s after start, want the configured timeout", deadline.Sub(start))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	deadline, _ = srv.deadline.Load().(time.Time)
+	if deadline.Before(start.Add(59 * time.Minute)) {
+		t.Errorf("server deadline is %s after start, want the deadline of the caller", deadline.Sub(start))
+	}
+}
+
+func TestToken(t *testing.T) {
+	srv := &healthServer{}
+	dialer := startHealthServer(t, srv)
+
+	tokenSource := TokenSource(func(context.Context) (string, error) { return "secret", nil })
+	conn, err := testConfig().DialContext(context.Background(), "passthrough:///bufnet", dialer,
+		grpc.WithChainUnaryInterceptor(TokenUnaryClientInterceptor(tokenSource)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if authorization, _ := srv.authorization.Load().(string); authorization != "Bearer secret" {
+		t.Errorf("authorization is %q, want Bearer secret", authorization)
+	}
+}
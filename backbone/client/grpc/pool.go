@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// Pool is a cache of client connections, keyed by target.
+//
+// Connections are dialed with the config and the DialOptions of the pool. The
+// DialOptions in the context (see NewContextWithDialOptions) are only used when
+// the connection to a target is dialed, so callers that need different options
+// should use different pools.
+type Pool struct {
+	config   *Config
+	dialOpts []grpc.DialOption
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewPool returns a new connection pool that dials connections using the config
+// and the given DialOptions. If config is nil, the default config is used.
+func NewPool(config *Config, opts ...grpc.DialOption) *Pool {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Pool{
+		config:   config,
+		dialOpts: opts,
+		conns:    make(map[string]*grpc.ClientConn),
+	}
+}
+
+// DialContext returns the connection to target from the pool, or dials a new connection.
+func (p *Pool) DialContext(ctx context.Context, target string) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	conn, ok := p.conns[target]
+	p.mu.Unlock()
+	if ok {
+		return conn, nil
+	}
+	conn, err := p.config.DialContext(ctx, target, p.dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.conns[target]; ok {
+		// Another caller dialed the same target in the meantime.
+		conn.Close()
+		return existing, nil
+	}
+	p.conns[target] = conn
+	return conn, nil
+}
+
+// Close closes all connections in the pool.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs []error
+	for target, conn := range p.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.conns, target)
+	}
+	return errors.Join(errs...)
+}
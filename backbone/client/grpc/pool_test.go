@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestPool(t *testing.T) {
+	srv := &healthServer{}
+	dialer := startHealthServer(t, srv)
+
+	ctx := context.Background()
+	pool := NewPool(testConfig(), dialer)
+
+	first, err := pool.DialContext(ctx, "passthrough:///first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := pool.DialContext(ctx, "passthrough:///first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("pool dialed a new connection to the same target")
+	}
+	second, err := pool.DialContext(ctx, "passthrough:///second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first {
+		t.Error("pool returned the same connection for a different target")
+	}
+
+	if _, err := healthpb.NewHealthClient(first).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, conn := range []interface{ GetState() connectivity.State }{first, second} {
+		if state := conn.GetState(); state != connectivity.Shutdown {
+			t.Errorf("connection is %s after closing the pool, want SHUTDOWN", state)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// CircuitBreakerConfig is the configuration for circuit breaking.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty"`
+	OpenDuration     time.Duration `json:"openDuration,omitempty" yaml:"openDuration,omitempty"`
+}
+
+// DefaultCircuitBreakerConfig returns the default configuration for circuit breaking.
+func DefaultCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{
+		FailureThreshold: 5,
+		OpenDuration:     10 * time.Second,
+	}
+}
+
+// Flags returns a flagset that can be added to the command line.
+func (c *CircuitBreakerConfig) Flags(prefix string, defaults *CircuitBreakerConfig) *pflag.FlagSet {
+	var flags pflag.FlagSet
+	if defaults == nil {
+		defaults = DefaultCircuitBreakerConfig()
+	}
+	flags.IntVar(&c.FailureThreshold, prefix+"failureThreshold", defaults.FailureThreshold, "Number of consecutive failures after which the circuit to a host opens (0 to disable)")
+	flags.DurationVar(&c.OpenDuration, prefix+"openDuration", defaults.OpenDuration, "Duration that the circuit to a host stays open before a request is let through")
+	return &flags
+}
+
+// ErrCircuitOpen is returned when the circuit to a host is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuit struct {
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+type circuitBreaker struct {
+	config  *CircuitBreakerConfig
+	options *options
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func (b *circuitBreaker) allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[host]
+	if !ok {
+		return true
+	}
+	switch c.state {
+	case circuitOpen:
+		if b.options.clock.Since(c.openedAt) < b.config.OpenDuration {
+			return false
+		}
+		c.state = circuitHalfOpen // Let one request through.
+		return true
+	case circuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) record(host string, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[host]
+	if success {
+		if ok {
+			delete(b.circuits, host)
+		}
+		return
+	}
+	if !ok {
+		c = &circuit{}
+		b.circuits[host] = c
+	}
+	c.failures++
+	if c.state == circuitHalfOpen || c.failures >= b.config.FailureThreshold {
+		c.state = circuitOpen
+		c.openedAt = b.options.clock.Now()
+	}
+}
+
+// CircuitBreaker returns middleware that keeps a circuit per host. After a number of
+// consecutive failures (transport errors or 5xx responses) the circuit opens, and
+// requests to that host fail with ErrCircuitOpen. After the open duration, a single
+// request is let through; if it succeeds the circuit closes, otherwise it opens again.
+func CircuitBreaker(config *CircuitBreakerConfig, opts ...Option) Middleware {
+	if config == nil {
+		config = DefaultCircuitBreakerConfig()
+	}
+	b := &circuitBreaker{
+		config:   config,
+		options:  newOptions(opts...),
+		circuits: make(map[string]*circuit),
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		if config.FailureThreshold <= 0 {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			host := req.URL.Host
+			if !b.allow(host) {
+				return nil, fmt.Errorf("%w to %s", ErrCircuitOpen, host)
+			}
+			res, err := next.RoundTrip(req)
+			b.record(host, err == nil && res.StatusCode < 500)
+			return res, err
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/spf13/pflag"
+)
+
+// Config is the configuration for HTTP clients.
+type Config struct {
+	Retry           RetryConfig          `json:"retry,omitempty" yaml:"retry,omitempty"`
+	CircuitBreaker  CircuitBreakerConfig `json:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
+	Log             bool                 `json:"log,omitempty" yaml:"log,omitempty"`
+	RedactedHeaders []string             `json:"redactedHeaders,omitempty" yaml:"redactedHeaders,omitempty"`
+}
+
+// DefaultConfig returns the default configuration for HTTP clients.
+func DefaultConfig() *Config {
+	return &Config{
+		Retry:          *DefaultRetryConfig(),
+		CircuitBreaker: *DefaultCircuitBreakerConfig(),
+	}
+}
+
+// Flags returns a flagset that can be added to the command line.
+func (c *Config) Flags(prefix string, defaults *Config) *pflag.FlagSet {
+	var flags pflag.FlagSet
+	if defaults == nil {
+		defaults = DefaultConfig()
+	}
+	flags.AddFlagSet(c.Retry.Flags(prefix+"retry.", &defaults.Retry))
+	flags.AddFlagSet(c.CircuitBreaker.Flags(prefix+"circuitBreaker.", &defaults.CircuitBreaker))
+	flags.BoolVar(&c.Log, prefix+"log", defaults.Log, "Log HTTP requests and responses")
+	flags.StringSliceVar(&c.RedactedHeaders, prefix+"log.redactedHeaders", defaults.RedactedHeaders, "Additional headers to redact in logs")
+	return &flags
+}
+
+// Middleware returns the middleware for the configuration. Requests are traced,
+// then retried, then passed through the circuit breaker and then logged, so that
+// each attempt is logged separately.
+func (c *Config) Middleware(opts ...Option) []Middleware {
+	middleware := []Middleware{
+		Tracing(),
+		Retry(&c.Retry, opts...),
+		CircuitBreaker(&c.CircuitBreaker, opts...),
+	}
+	if c.Log {
+		middleware = append(middleware, Logging(c.RedactedHeaders, opts...))
+	}
+	return middleware
+}
+
+// RoundTripper returns the RoundTripper for the configuration on top of base.
+// If base is nil, http.DefaultTransport is used.
+func (c *Config) RoundTripper(base http.RoundTripper, opts ...Option) http.RoundTripper {
+	return Chain(base, c.Middleware(opts...)...)
+}
+
+// Client returns a new HTTP Client for the configuration.
+func (c *Config) Client(opts ...Option) *http.Client {
+	return &http.Client{Transport: c.RoundTripper(nil, opts...)}
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+func formatHeader(header http.Header, redact map[string]struct{}) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		value := strings.Join(header[key], ", ")
+		if _, ok := redact[key]; ok {
+			value = "<redacted>"
+		}
+		b.WriteString(" ")
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+// Logging returns middleware that logs requests and responses, including their headers.
+// The values of the Authorization, Proxy-Authorization, Cookie and Set-Cookie
+// headers, and the given redactedHeaders are redacted.
+func Logging(redactedHeaders []string, opts ...Option) Middleware {
+	options := newOptions(opts...)
+	redact := make(map[string]struct{})
+	for _, header := range append(defaultRedactedHeaders, redactedHeaders...) {
+		redact[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := options.clock.Now()
+			options.logger.Printf("HTTP request: %s %s%s", req.Method, req.URL.Redacted(), formatHeader(req.Header, redact))
+			res, err := next.RoundTrip(req)
+			duration := options.clock.Since(start).Round(time.Millisecond)
+			if err != nil {
+				options.logger.Printf("HTTP request failed: %s %s (%s): %v", req.Method, req.URL.Redacted(), duration, err)
+				return res, err
+			}
+			options.logger.Printf("HTTP response: %s %s (%s): %s%s", req.Method, req.URL.Redacted(), duration, res.Status, formatHeader(res.Header, redact))
+			return res, nil
+		})
+	}
+}
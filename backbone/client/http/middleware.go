@@ -0,0 +1,73 @@
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/benbjohnson/clock"
+)
+
+// RoundTripperFunc is a function that implements http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a RoundTripper.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// Chain returns a RoundTripper that passes requests through the middleware before
+// they are sent by base. The first middleware is the outermost one.
+// If base is nil, http.DefaultTransport is used.
+func Chain(base http.RoundTripper, middleware ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		base = middleware[i](base)
+	}
+	return base
+}
+
+type options struct {
+	clock  clock.Clock
+	logger *log.Logger
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		clock:  clock.New(),
+		logger: log.Default(),
+	}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+	return o
+}
+
+// Option is an option for the client middleware.
+type Option interface {
+	apply(*options)
+}
+
+type option func(*options)
+
+func (f option) apply(opts *options) {
+	f(opts)
+}
+
+// WithClock returns an option that sets the clock that is used for backoff and circuit breaking.
+func WithClock(clock clock.Clock) Option {
+	return option(func(opts *options) {
+		opts.clock = clock
+	})
+}
+
+// WithLogger returns an option that sets the logger that is used for request logging.
+func WithLogger(logger *log.Logger) Option {
+	return option(func(opts *options) {
+		opts.logger = logger
+	})
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+func TestRetry(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Chain(nil, Retry(&RetryConfig{
+		MaxAttempts:       3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        time.Millisecond,
+		BackoffMultiplier: 2,
+		MaxRetryAfter:     time.Second,
+	}))}
+
+	res, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || attempts != 3 {
+		t.Errorf("got status %d after %d attempts, expected 200 after 3 attempts", res.StatusCode, attempts)
+	}
+
+	attempts = 0
+	res, err = client.Post(srv.URL, "text/plain", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if attempts != 1 {
+		t.Errorf("POST request was attempted %d times, expected 1", attempts)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := clock.NewMock()
+	var fail bool
+	base := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if fail {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	rt := Chain(base, CircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenDuration:     time.Second,
+	}, WithClock(clock)))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+
+	fail = true
+	for i := 0; i < 2; i++ {
+		if _, err := rt.RoundTrip(req); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected request error, got %v", err)
+		}
+	}
+	if _, err := rt.RoundTrip(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	clock.Add(time.Second)
+	fail = false
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("expected half-open request to succeed, got %v", err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("expected closed circuit, got %v", err)
+	}
+}
+
+func TestRetryCircuitOpen(t *testing.T) {
+	var attempts int
+	base := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	})
+	rt := Chain(base,
+		Retry(&RetryConfig{
+			MaxAttempts:       5,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        time.Millisecond,
+			BackoffMultiplier: 2,
+		}),
+		CircuitBreaker(&CircuitBreakerConfig{
+			FailureThreshold: 2,
+			OpenDuration:     time.Minute,
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if _, err := rt.RoundTrip(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("request was attempted %d times, expected 2", attempts)
+	}
+
+	attempts = 0
+	if _, err := rt.RoundTrip(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if attempts != 0 {
+		t.Errorf("request was attempted %d times with open circuit, expected 0", attempts)
+	}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// RetryConfig is the configuration for retrying requests.
+type RetryConfig struct {
+	MaxAttempts       int           `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+	InitialBackoff    time.Duration `json:"initialBackoff,omitempty" yaml:"initialBackoff,omitempty"`
+	MaxBackoff        time.Duration `json:"maxBackoff,omitempty" yaml:"maxBackoff,omitempty"`
+	BackoffMultiplier float64       `json:"backoffMultiplier,omitempty" yaml:"backoffMultiplier,omitempty"`
+	MaxRetryAfter     time.Duration `json:"maxRetryAfter,omitempty" yaml:"maxRetryAfter,omitempty"`
+}
+
+// DefaultRetryConfig returns the default configuration for retrying requests.
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxAttempts:       3,
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        5 * time.Second,
+		BackoffMultiplier: 2,
+		MaxRetryAfter:     30 * time.Second,
+	}
+}
+
+// Flags returns a flagset that can be added to the command line.
+func (c *RetryConfig) Flags(prefix string, defaults *RetryConfig) *pflag.FlagSet {
+	var flags pflag.FlagSet
+	if defaults == nil {
+		defaults = DefaultRetryConfig()
+	}
+	flags.IntVar(&c.MaxAttempts, prefix+"maxAttempts", defaults.MaxAttempts, "Maximum number of attempts for requests (including the original request)")
+	flags.DurationVar(&c.InitialBackoff, prefix+"initialBackoff", defaults.InitialBackoff, "Initial backoff between retries")
+	flags.DurationVar(&c.MaxBackoff, prefix+"maxBackoff", defaults.MaxBackoff, "Maximum backoff between retries")
+	flags.Float64Var(&c.BackoffMultiplier, prefix+"backoffMultiplier", defaults.BackoffMultiplier, "Multiplier for the backoff between retries")
+	flags.DurationVar(&c.MaxRetryAfter, prefix+"maxRetryAfter", defaults.MaxRetryAfter, "Maximum Retry-After duration that is honored (longer durations are not retried)")
+	return &flags
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header of the response, which can be either
+// a number of seconds, or an HTTP date.
+func retryAfter(res *http.Response, now time.Time) (time.Duration, bool) {
+	header := res.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func (c *RetryConfig) backoff(attempt int) time.Duration {
+	backoff := float64(c.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		backoff *= c.BackoffMultiplier
+		if backoff > float64(c.MaxBackoff) {
+			backoff = float64(c.MaxBackoff)
+			break
+		}
+	}
+	// Use half of the backoff as jitter.
+	return time.Duration(backoff/2 + rand.Float64()*backoff/2)
+}
+
+// Retry returns middleware that retries idempotent requests with exponential
+// backoff and jitter when they fail with a transport error or a retryable
+// status code. If the server responds with a Retry-After header, that is used
+// instead of the backoff.
+//
+// Requests that fail with ErrCircuitOpen are not retried, so Retry can wrap
+// a CircuitBreaker without backing off on a circuit that is open.
+//
+// Requests with a body are only retried if their GetBody func is set.
+func Retry(config *RetryConfig, opts ...Option) Middleware {
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
+	options := newOptions(opts...)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if config.MaxAttempts <= 1 || !isIdempotent(req) || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+				return next.RoundTrip(req)
+			}
+			for attempt := 1; ; attempt++ {
+				res, err := next.RoundTrip(req)
+				if attempt >= config.MaxAttempts {
+					return res, err
+				}
+				wait := config.backoff(attempt)
+				if err == nil {
+					if !isRetryableStatus(res.StatusCode) {
+						return res, nil
+					}
+					if d, ok := retryAfter(res, options.clock.Now()); ok {
+						if d > config.MaxRetryAfter {
+							return res, nil
+						}
+						wait = d
+					}
+					io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+					res.Body.Close()
+				} else if req.Context().Err() != nil || errors.Is(err, ErrCircuitOpen) {
+					return nil, err
+				}
+				if err := sleep(req.Context(), options, wait); err != nil {
+					return nil, err
+				}
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					req = req.Clone(req.Context())
+					req.Body = body
+				}
+			}
+		})
+	}
+}
+
+func sleep(ctx context.Context, options *options, d time.Duration) error {
+	timer := options.clock.Timer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
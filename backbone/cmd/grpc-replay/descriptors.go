@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// descriptors resolves method descriptors from descriptor sets, and from
+// the server reflection service of the target server.
+type descriptors struct {
+	cc     grpc.ClientConnInterface
+	files  *protoregistry.Files
+	protos map[string]*descriptorpb.FileDescriptorProto
+}
+
+func newDescriptors(cc grpc.ClientConnInterface) *descriptors {
+	return &descriptors{
+		cc:     cc,
+		files:  new(protoregistry.Files),
+		protos: make(map[string]*descriptorpb.FileDescriptorProto),
+	}
+}
+
+func (d *descriptors) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if file, err := d.files.FindFileByPath(path); err == nil {
+		return file, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (d *descriptors) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if desc, err := d.files.FindDescriptorByName(name); err == nil {
+		return desc, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// loadDescriptorSet loads the files in a descriptor set, such as the one written by
+// protoc --descriptor_set_out.
+func (d *descriptors) loadDescriptorSet(ctx context.Context, name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("invalid descriptor set %q: %w", name, err)
+	}
+	for _, file := range set.GetFile() {
+		d.protos[file.GetName()] = file
+	}
+	for _, file := range set.GetFile() {
+		if err := d.register(ctx, file.GetName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// register registers a file and its dependencies. Files that are not known yet
+// are requested from the server reflection service.
+func (d *descriptors) register(ctx context.Context, name string) error {
+	if _, err := d.files.FindFileByPath(name); err == nil {
+		return nil
+	}
+	fileProto, ok := d.protos[name]
+	if !ok {
+		if _, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+			return nil
+		}
+		if err := d.reflect(ctx, &rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+		}); err != nil {
+			return err
+		}
+		if fileProto, ok = d.protos[name]; !ok {
+			return fmt.Errorf("file %q not found", name)
+		}
+	}
+	for _, dependency := range fileProto.GetDependency() {
+		// Servers may not know all dependencies, such as files that only define options.
+		// Those are not needed to replay calls, so unresolvable files are allowed.
+		d.register(ctx, dependency)
+	}
+	file, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fileProto, d)
+	if err != nil {
+		return fmt.Errorf("invalid file %q: %w", name, err)
+	}
+	return d.files.RegisterFile(file)
+}
+
+// reflect sends a request to the server reflection service, and adds the
+// files in the response to the known files.
+func (d *descriptors) reflect(ctx context.Context, req *rpb.ServerReflectionRequest) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := rpb.NewServerReflectionClient(d.cc).ServerReflectionInfo(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(req); err != nil {
+		return err
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("server reflection failed: %w", err)
+	}
+	if errRes := res.GetErrorResponse(); errRes != nil {
+		return fmt.Errorf("server reflection failed: %s", errRes.GetErrorMessage())
+	}
+	for _, data := range res.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		var fileProto descriptorpb.FileDescriptorProto
+		if err := proto.Unmarshal(data, &fileProto); err != nil {
+			return err
+		}
+		if _, ok := d.protos[fileProto.GetName()]; !ok {
+			d.protos[fileProto.GetName()] = &fileProto
+		}
+	}
+	return nil
+}
+
+// method returns the descriptor of a method, given its full gRPC method name
+// such as "/pkg.Service/Method".
+func (d *descriptors) method(ctx context.Context, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	service, method, ok := splitMethod(fullMethod)
+	if !ok {
+		return nil, fmt.Errorf("invalid method %q", fullMethod)
+	}
+	desc, err := d.FindDescriptorByName(service)
+	if errors.Is(err, protoregistry.NotFound) {
+		if err := d.reflect(ctx, &rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: string(service)},
+		}); err != nil {
+			return nil, err
+		}
+		for name, fileProto := range d.protos {
+			if containsService(fileProto, service) {
+				if err := d.register(ctx, name); err != nil {
+					return nil, err
+				}
+			}
+		}
+		desc, err = d.FindDescriptorByName(service)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service %q not found: %w", service, err)
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", service)
+	}
+	methodDesc := serviceDesc.Methods().ByName(method)
+	if methodDesc == nil {
+		return nil, fmt.Errorf("method %q not found", fullMethod)
+	}
+	return methodDesc, nil
+}
+
+func containsService(fileProto *descriptorpb.FileDescriptorProto, service protoreflect.FullName) bool {
+	for _, s := range fileProto.GetService() {
+		if protoreflect.FullName(fileProto.GetPackage()).Append(protoreflect.Name(s.GetName())) == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,395 @@
+// Command grpc-replay replays gRPC calls that were recorded with the recording
+// package against a server, and shows the differences between the recorded and
+// the replayed responses.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	bbgrpc "htdvisser.dev/exp/backbone/client/grpc"
+	"htdvisser.dev/exp/backbone/server/grpc/recording"
+	"htdvisser.dev/exp/clicontext"
+	"htdvisser.dev/exp/fieldpath"
+	"htdvisser.dev/exp/pflagenv"
+)
+
+var config struct {
+	GRPCAddress     string
+	GRPCTLS         bool
+	DescriptorSets  []string
+	Headers         []string
+	ForwardMetadata bool
+	Ignore          []string
+}
+
+func init() {
+	pflag.StringVar(&config.GRPCAddress, "grpc.address", "localhost:9090", "Address of the gRPC server")
+	pflag.BoolVar(&config.GRPCTLS, "grpc.tls", false, "Use TLS to connect to the gRPC server")
+	pflag.StringSliceVar(&config.DescriptorSets, "descriptor-set", nil, "Descriptor sets with the services (uses server reflection if not found)")
+	pflag.StringArrayVar(&config.Headers, "header", nil, "Metadata to add to replayed calls (key=value)")
+	pflag.BoolVar(&config.ForwardMetadata, "forward-metadata", true, "Send the recorded metadata with replayed calls")
+	pflag.StringSliceVar(&config.Ignore, "ignore", nil, "Field paths to ignore when comparing responses")
+	pflag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: grpc-replay [flags] recording ...")
+		pflag.PrintDefaults()
+	}
+}
+
+func main() {
+	ctx, exit := clicontext.WithInterruptAndExit(context.Background())
+	defer exit()
+
+	if err := pflagenv.NewParser(pflagenv.Prefixes("grpc_replay_")).ParseEnv(pflag.CommandLine); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		pflag.Usage()
+		os.Exit(2)
+	}
+
+	pflag.Parse()
+
+	if pflag.NArg() == 0 {
+		pflag.Usage()
+		os.Exit(2)
+	}
+
+	if err := Main(ctx, pflag.Args()...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+var marshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// skipMetadata returns whether a recorded metadata key should not be sent,
+// because it is set by the gRPC client or transport.
+func skipMetadata(key string) bool {
+	switch {
+	case strings.HasPrefix(key, ":"), strings.HasPrefix(key, "grpc-"):
+		return true
+	case key == "content-type", key == "user-agent", key == "te":
+		return true
+	default:
+		return false
+	}
+}
+
+// Main replays the recordings in the given files.
+func Main(ctx context.Context, files ...string) error {
+	if config.GRPCTLS {
+		ctx = bbgrpc.NewContextWithDialOptions(ctx, grpc.WithTransportCredentials(credentials.NewTLS(nil)))
+	} else {
+		ctx = bbgrpc.NewContextWithDialOptions(ctx, grpc.WithInsecure())
+	}
+
+	headers := make(metadata.MD)
+	for _, header := range config.Headers {
+		key, value, ok := strings.Cut(header, "=")
+		if !ok {
+			return fmt.Errorf("invalid header %q", header)
+		}
+		headers.Append(strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value))
+	}
+	ignore, err := fieldpath.ParseList(config.Ignore...)
+	if err != nil {
+		return err
+	}
+
+	cc, err := bbgrpc.DialContext(ctx, config.GRPCAddress)
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	descriptors := newDescriptors(cc)
+	for _, name := range config.DescriptorSets {
+		if err := descriptors.loadDescriptorSet(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	r := &replayer{
+		cc:          cc,
+		descriptors: descriptors,
+		headers:     headers,
+		ignore:      ignore,
+	}
+	for _, name := range files {
+		if err := r.replayFile(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Replayed %d calls: %d matched, %d differed, %d failed\n", r.calls, r.calls-r.differed-r.failed, r.differed, r.failed)
+	if r.differed > 0 || r.failed > 0 {
+		return errors.New("replayed calls did not match the recording")
+	}
+	return nil
+}
+
+type replayer struct {
+	cc          grpc.ClientConnInterface
+	descriptors *descriptors
+	headers     metadata.MD
+	ignore      fieldpath.List
+
+	calls, differed, failed int
+}
+
+func (r *replayer) replayFile(ctx context.Context, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// The records are read before replaying them, because the target server may
+	// be recording to the same file.
+	var records []*recording.Record
+	if err := recording.ReadRecords(f, func(record *recording.Record) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r.calls++
+		differences, err := r.replay(ctx, record)
+		switch {
+		case err != nil:
+			r.failed++
+			fmt.Printf("%s (recorded %s): %v\n", record.Method, record.Time.Format("2006-01-02T15:04:05.000Z07:00"), err)
+		case len(differences) > 0:
+			r.differed++
+			fmt.Printf("%s (recorded %s): differs from recording\n", record.Method, record.Time.Format("2006-01-02T15:04:05.000Z07:00"))
+			for _, difference := range differences {
+				fmt.Printf("  %s\n", difference)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *replayer) metadata(record *recording.Record) (metadata.MD, error) {
+	md := make(metadata.MD)
+	if config.ForwardMetadata {
+		recorded, err := record.Metadata.MD()
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range recorded {
+			if skipMetadata(key) {
+				continue
+			}
+			for _, value := range values {
+				if value != recording.RedactedValue {
+					md.Append(key, value)
+				}
+			}
+		}
+	}
+	for key, values := range r.headers {
+		md.Set(key, values...)
+	}
+	return md, nil
+}
+
+// replay replays a recorded call, and returns the differences with the recording.
+func (r *replayer) replay(ctx context.Context, record *recording.Record) ([]string, error) {
+	method, err := r.descriptors.method(ctx, record.Method)
+	if err != nil {
+		return nil, err
+	}
+	md, err := r.metadata(record)
+	if err != nil {
+		return nil, err
+	}
+	unmarshalOptions := protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+		Resolver:       dynamicpb.NewTypes(r.descriptors.files),
+	}
+	requests := make([]proto.Message, len(record.Requests))
+	for i, data := range record.Requests {
+		requests[i] = dynamicpb.NewMessage(method.Input())
+		if err := unmarshalOptions.Unmarshal(data, requests[i]); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ctx, md))
+	defer cancel()
+	stream, err := r.cc.NewStream(ctx, &grpc.StreamDesc{
+		ClientStreams: method.IsStreamingClient(),
+		ServerStreams: method.IsStreamingServer(),
+	}, record.Method)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		if err := stream.SendMsg(req); err != nil {
+			break // The status is returned by RecvMsg.
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	var responses []proto.Message
+	for {
+		res := dynamicpb.NewMessage(method.Output())
+		if err = stream.RecvMsg(res); err != nil {
+			break
+		}
+		responses = append(responses, res)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return r.compare(record, responses, status.Convert(err))
+}
+
+func (r *replayer) compare(record *recording.Record, responses []proto.Message, s *status.Status) ([]string, error) {
+	var differences []string
+	if code := s.Code().String(); code != record.Code {
+		differences = append(differences, fmt.Sprintf("code: recorded %s, replayed %s", record.Code, code))
+	}
+	if s.Message() != record.Message {
+		differences = append(differences, fmt.Sprintf("message: recorded %q, replayed %q", record.Message, s.Message()))
+	}
+	n := len(responses)
+	if len(record.Responses) < n && record.Truncated {
+		n = len(record.Responses)
+	}
+	if len(record.Responses) != n {
+		differences = append(differences, fmt.Sprintf("responses: recorded %d, replayed %d", len(record.Responses), n))
+	}
+	ignore := r.ignore
+	for _, redacted := range record.Redacted {
+		if fp, err := fieldpath.Parse(redacted); err == nil {
+			ignore = append(ignore, fp)
+		}
+	}
+	for i := 0; i < n && i < len(record.Responses); i++ {
+		var recorded, replayed fieldpath.Map
+		if err := json.Unmarshal(record.Responses[i], &recorded); err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+		data, err := marshalOptions.Marshal(responses[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &replayed); err != nil {
+			return nil, err
+		}
+		desc := responses[i].ProtoReflect().Descriptor()
+		for _, fp := range ignore {
+			removeField(map[string]interface{}(recorded), desc, fp)
+			removeField(map[string]interface{}(replayed), desc, fp)
+		}
+		prefix := ""
+		if record.ServerStreams {
+			prefix = fmt.Sprintf("responses[%d].", i)
+		}
+		differences = append(differences, diff(prefix, recorded, replayed, ignore)...)
+	}
+	return differences, nil
+}
+
+// removeField removes the field at fp from the JSON form of a message of type desc.
+// If fp goes through a repeated field or a map field, the field is removed from each
+// of the elements or values, in the same way as the recording package redacts fields.
+func removeField(v interface{}, desc protoreflect.MessageDescriptor, fp fieldpath.Path) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(fp) == 0 {
+		return
+	}
+	fd := desc.Fields().ByName(protoreflect.Name(fp[0]))
+	if fd == nil {
+		return
+	}
+	if len(fp) == 1 {
+		delete(m, fp[0])
+		return
+	}
+	switch {
+	case fd.IsMap():
+		if fd.MapValue().Message() == nil {
+			return
+		}
+		values, _ := m[fp[0]].(map[string]interface{})
+		for _, value := range values {
+			removeField(value, fd.MapValue().Message(), fp[1:])
+		}
+	case fd.Message() == nil:
+		return
+	case fd.IsList():
+		values, _ := m[fp[0]].([]interface{})
+		for _, value := range values {
+			removeField(value, fd.Message(), fp[1:])
+		}
+	default:
+		removeField(m[fp[0]], fd.Message(), fp[1:])
+	}
+}
+
+// diff returns the differences between the fields of two messages in JSON form.
+func diff(prefix string, recorded, replayed fieldpath.Map, ignore fieldpath.List) []string {
+	fields := make(map[string]fieldpath.Path)
+	for _, fp := range append(recorded.Fields(), replayed.Fields()...) {
+		if !ignore.Contains(fp, false) {
+			fields[fp.String()] = fp
+		}
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var differences []string
+	for _, name := range names {
+		recordedValue, _ := recorded.Get(fields[name])
+		replayedValue, _ := replayed.Get(fields[name])
+		if !reflect.DeepEqual(recordedValue, replayedValue) {
+			differences = append(differences, fmt.Sprintf("%s%s: recorded %s, replayed %s", prefix, name, jsonString(recordedValue), jsonString(replayedValue)))
+		}
+	}
+	return differences
+}
+
+func jsonString(v interface{}) string {
+	if v == nil {
+		return "(unset)"
+	}
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func splitMethod(fullMethod string) (protoreflect.FullName, protoreflect.Name, bool) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok || service == "" || method == "" {
+		return "", "", false
+	}
+	return protoreflect.FullName(service), protoreflect.Name(method), true
+}
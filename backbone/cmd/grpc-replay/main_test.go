@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/typepb"
+	"htdvisser.dev/exp/backbone/server/grpc/recording"
+	"htdvisser.dev/exp/fieldpath"
+)
+
+func TestDiff(t *testing.T) {
+	var recorded, replayed fieldpath.Map
+	if err := json.Unmarshal([]byte(`{"name": "a", "nested": {"kept": 1, "changed": "x", "ignored": 1}, "removed": true, "list": [1, 2]}`), &recorded); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"name": "a", "nested": {"kept": 1, "changed": "y", "ignored": 2}, "added": "new", "list": [1, 3]}`), &replayed); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := fieldpath.ParseList("nested.ignored")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`responses[1].added: recorded (unset), replayed "new"`,
+		`responses[1].list: recorded [1,2], replayed [1,3]`,
+		`responses[1].nested.changed: recorded "x", replayed "y"`,
+		`responses[1].removed: recorded true, replayed (unset)`,
+	}
+	if differences := diff("responses[1].", recorded, replayed, ignore); !reflect.DeepEqual(differences, expected) {
+		t.Errorf("differences are %q, want %q", differences, expected)
+	}
+}
+
+func mustMarshal(t *testing.T, msg proto.Message) json.RawMessage {
+	t.Helper()
+	data, err := marshalOptions.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestCompare(t *testing.T) {
+	newType := func(name string, fields ...string) *typepb.Type {
+		typ := &typepb.Type{Name: name}
+		for _, field := range fields {
+			typ.Fields = append(typ.Fields, &typepb.Field{Name: field, Number: 1})
+		}
+		return typ
+	}
+	recordedResponses := []json.RawMessage{
+		mustMarshal(t, newType("first", "a")),
+		mustMarshal(t, newType("second", "b")),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		record   *recording.Record
+		replayed []proto.Message
+		status   *status.Status
+		ignore   []string
+		expected []string
+	}{
+		{
+			name:     "equal",
+			record:   &recording.Record{ServerStreams: true, Code: "OK", Responses: recordedResponses},
+			replayed: []proto.Message{newType("first", "a"), newType("second", "b")},
+			status:   status.New(codes.OK, ""),
+		},
+		{
+			name:     "status",
+			record:   &recording.Record{Code: "NotFound", Message: "not found"},
+			status:   status.New(codes.Unavailable, "unavailable"),
+			expected: []string{`code: recorded NotFound, replayed Unavailable`, `message: recorded "not found", replayed "unavailable"`},
+		},
+		{
+			name:     "fewer responses",
+			record:   &recording.Record{ServerStreams: true, Code: "OK", Responses: recordedResponses},
+			replayed: []proto.Message{newType("first", "a")},
+			status:   status.New(codes.OK, ""),
+			expected: []string{`responses: recorded 2, replayed 1`},
+		},
+		{
+			name:     "more responses",
+			record:   &recording.Record{ServerStreams: true, Code: "OK", Responses: recordedResponses},
+			replayed: []proto.Message{newType("first", "a"), newType("second", "b"), newType("third")},
+			status:   status.New(codes.OK, ""),
+			expected: []string{`responses: recorded 2, replayed 3`},
+		},
+		{
+			name:     "more responses than truncated recording",
+			record:   &recording.Record{ServerStreams: true, Code: "OK", Responses: recordedResponses, Truncated: true},
+			replayed: []proto.Message{newType("first", "a"), newType("second", "b"), newType("third")},
+			status:   status.New(codes.OK, ""),
+		},
+		{
+			name:     "fewer responses than truncated recording",
+			record:   &recording.Record{ServerStreams: true, Code: "OK", Responses: recordedResponses, Truncated: true},
+			replayed: []proto.Message{newType("first", "a")},
+			status:   status.New(codes.OK, ""),
+			expected: []string{`responses: recorded 2, replayed 1`},
+		},
+		{
+			name:     "changed fields",
+			record:   &recording.Record{Code: "OK", Responses: recordedResponses[:1]},
+			replayed: []proto.Message{newType("changed", "a")},
+			status:   status.New(codes.OK, ""),
+			expected: []string{`name: recorded "first", replayed "changed"`},
+		},
+		{
+			name:     "ignored fields",
+			record:   &recording.Record{Code: "OK", Responses: recordedResponses[:1]},
+			replayed: []proto.Message{newType("changed", "a")},
+			status:   status.New(codes.OK, ""),
+			ignore:   []string{"name"},
+		},
+		{
+			name:     "ignored fields in repeated fields",
+			record:   &recording.Record{Code: "OK", Responses: recordedResponses[:1]},
+			replayed: []proto.Message{newType("first", "changed")},
+			status:   status.New(codes.OK, ""),
+			ignore:   []string{"fields.name"},
+		},
+		{
+			name: "redacted fields in repeated fields",
+			record: &recording.Record{
+				Code:      "OK",
+				Responses: []json.RawMessage{json.RawMessage(`{"name":"first","fields":[{"number":1}]}`)},
+				Redacted:  []string{"fields.name"},
+			},
+			replayed: []proto.Message{newType("first", "a")},
+			status:   status.New(codes.OK, ""),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore, err := fieldpath.ParseList(tc.ignore...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := &replayer{ignore: ignore}
+			differences, err := r.compare(tc.record, tc.replayed, tc.status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(differences, tc.expected) {
+				t.Errorf("differences are %q, want %q", differences, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	defer func(forwardMetadata bool) { config.ForwardMetadata = forwardMetadata }(config.ForwardMetadata)
+
+	record := &recording.Record{
+		Metadata: recording.Metadata{
+			":authority":    {"localhost:9090"},
+			"content-type":  {"application/grpc"},
+			"user-agent":    {"grpc-go/1.60.1"},
+			"grpc-timeout":  {"1S"},
+			"authorization": {recording.RedactedValue},
+			"x-request-id":  {"recorded"},
+			"x-tenant-id":   {"tenant"},
+			"x-trace-bin":   {"AAE="},
+		},
+	}
+	r := &replayer{headers: metadata.Pairs("x-request-id", "replayed", "authorization", "Bearer replay")}
+
+	config.ForwardMetadata = true
+	md, err := r.metadata(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := metadata.Pairs(
+		"authorization", "Bearer replay",
+		"x-request-id", "replayed",
+		"x-tenant-id", "tenant",
+		"x-trace-bin", "\x00\x01",
+	)
+	if !reflect.DeepEqual(md, expected) {
+		t.Errorf("metadata is %v, want %v", md, expected)
+	}
+
+	config.ForwardMetadata = false
+	if md, err = r.metadata(record); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(md, r.headers) {
+		t.Errorf("metadata is %v, want only the headers %v", md, r.headers)
+	}
+
+	record.Metadata["x-trace-bin"] = []string{"not base64"}
+	config.ForwardMetadata = true
+	if _, err := r.metadata(record); err == nil {
+		t.Error("expected error for invalid binary metadata")
+	}
+}
@@ -0,0 +1,465 @@
+// Package config loads configuration from config files, environment and command line flags into a FlagSet.
+//
+// Config types in the backbone (and the packages that it uses) expose their configuration
+// as flags. The Loader fills those flags from (in order of increasing priority) their defaults,
+// YAML or JSON config files, environment variables and command line flags.
+//
+// Keys in config files are the names of the flags. Nested objects are joined with dots,
+// and underscores are treated as dashes, so the following files are equivalent:
+//
+//	http.listen: ":8080"
+//
+//	http:
+//	  listen: ":8080"
+//
+//	{"http": {"listen": ":8080"}}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+	"htdvisser.dev/exp/pflagenv"
+)
+
+// Sources of configuration values.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// DefaultConfigFlag is the name of the flag that contains the config files.
+const DefaultConfigFlag = "config"
+
+type options struct {
+	configFlag   string
+	defaultFiles []string
+	envOptions   []pflagenv.ParserOption
+}
+
+// Option is an option for the Loader.
+type Option interface {
+	apply(*options)
+}
+
+type option func(*options)
+
+func (f option) apply(opts *options) {
+	f(opts)
+}
+
+// WithConfigFlag returns an option that changes the name of the flag that contains the config files.
+func WithConfigFlag(name string) Option {
+	return option(func(o *options) {
+		o.configFlag = name
+	})
+}
+
+// WithDefaultFiles returns an option that sets the config files that are loaded
+// if no config files are given. Default files that do not exist are skipped.
+func WithDefaultFiles(names ...string) Option {
+	return option(func(o *options) {
+		o.defaultFiles = names
+	})
+}
+
+// WithEnvOptions returns an option that adds options for the environment parser.
+func WithEnvOptions(opts ...pflagenv.ParserOption) Option {
+	return option(func(o *options) {
+		o.envOptions = append(o.envOptions, opts...)
+	})
+}
+
+// Loader loads configuration into a FlagSet.
+type Loader struct {
+	flagSet      *pflag.FlagSet
+	configFiles  *[]string
+	defaultFiles []string
+	envParser    *pflagenv.Parser
+
+	mu        sync.RWMutex
+	sources   map[string]string
+	dynamic   map[string]struct{}
+	reloaders []func()
+}
+
+// NewLoader returns a new Loader for the given FlagSet.
+// It adds a flag for the config files to the FlagSet.
+func NewLoader(flagSet *pflag.FlagSet, opts ...Option) *Loader {
+	options := &options{
+		configFlag: DefaultConfigFlag,
+	}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	return &Loader{
+		flagSet:      flagSet,
+		configFiles:  flagSet.StringSlice(options.configFlag, nil, "Config files (YAML or JSON) to load"),
+		defaultFiles: options.defaultFiles,
+		envParser:    pflagenv.NewParser(options.envOptions...),
+		sources:      make(map[string]string),
+		dynamic:      make(map[string]struct{}),
+	}
+}
+
+// recordingValue is a pflag.Value that records whether it was set.
+type recordingValue struct {
+	pflag.Value
+	set bool
+}
+
+func (v *recordingValue) Set(s string) error {
+	v.set = true
+	return v.Value.Set(s)
+}
+
+// Load parses the command line arguments and environment, and loads the config files.
+// Values from the command line take priority over values from the environment,
+// which take priority over values from config files.
+func (l *Loader) Load(args []string) error {
+	if err := l.flagSet.Parse(args); err != nil {
+		return err
+	}
+	sources := make(map[string]string)
+	l.flagSet.VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			sources[flag.Name] = SourceFlag
+		}
+	})
+
+	recorders := make(map[string]*recordingValue)
+	l.flagSet.VisitAll(func(flag *pflag.Flag) {
+		if _, ok := sources[flag.Name]; ok {
+			return
+		}
+		recorders[flag.Name] = &recordingValue{Value: flag.Value}
+		flag.Value = recorders[flag.Name]
+	})
+	err := l.envParser.ParseEnv(l.envFlagSet(recorders))
+	for name, recorder := range recorders {
+		l.flagSet.Lookup(name).Value = recorder.Value
+		if recorder.set {
+			sources[name] = SourceEnv
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.sources = sources
+	l.mu.Unlock()
+
+	if err := l.LoadFiles(); err != nil {
+		return err
+	}
+	l.notify()
+	return nil
+}
+
+// envFlagSet returns a FlagSet with only the flags that can still be set from the environment.
+func (l *Loader) envFlagSet(recorders map[string]*recordingValue) *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("env", pflag.ContinueOnError)
+	l.flagSet.VisitAll(func(flag *pflag.Flag) {
+		if _, ok := recorders[flag.Name]; ok {
+			flagSet.AddFlag(flag)
+		}
+	})
+	return flagSet
+}
+
+// Files returns the config files that are loaded.
+func (l *Loader) Files() []string {
+	if len(*l.configFiles) > 0 {
+		return *l.configFiles
+	}
+	var files []string
+	for _, name := range l.defaultFiles {
+		if _, err := os.Stat(name); err == nil {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// LoadFiles (re)loads the config files. Values in later files take priority over values
+// in earlier files. Flags that were set from the command line or environment are not changed.
+// If the config files contain an invalid value, none of the flags are changed.
+func (l *Loader) LoadFiles() error {
+	return l.loadFiles(false)
+}
+
+func (l *Loader) loadFiles(dynamicOnly bool) error {
+	files := l.Files()
+	values := make(map[string]interface{})
+	fileSources := make(map[string]string)
+	for _, name := range files {
+		fileValues, err := readFile(name)
+		if err != nil {
+			return err
+		}
+		flatValues := make(map[string]interface{})
+		for key, value := range fileValues {
+			if err := l.flatten(key, value, flatValues); err != nil {
+				return fmt.Errorf("config: invalid config file %q: %w", name, err)
+			}
+		}
+		for key, value := range flatValues {
+			values[key] = value
+			fileSources[key] = name
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Validate all values before changing any flags, so that an invalid value
+	// does not leave the config partially loaded.
+	var set []string
+	for name, value := range values {
+		switch l.sources[name] {
+		case SourceFlag, SourceEnv:
+			continue
+		}
+		if dynamicOnly && !l.isDynamic(name) {
+			continue
+		}
+		if err := validateValue(l.flagSet.Lookup(name), value); err != nil {
+			return fmt.Errorf("config: invalid value for %q in config file %q: %w", name, fileSources[name], err)
+		}
+		set = append(set, name)
+	}
+	sort.Strings(set)
+
+	var reset []string
+	for name, source := range l.sources {
+		if _, ok := values[name]; ok || !strings.HasPrefix(source, SourceFile) {
+			continue
+		}
+		if dynamicOnly && !l.isDynamic(name) {
+			continue
+		}
+		reset = append(reset, name)
+	}
+
+	// Values of types that can not be validated beforehand may still fail to be set.
+	// In that case we restore the values that were already changed.
+	var changed []*flagSnapshot
+	restore := func() {
+		for _, snapshot := range changed {
+			snapshot.restore()
+		}
+	}
+	for _, name := range reset {
+		// The value was removed from the config files, so we reset it to its default.
+		flag := l.flagSet.Lookup(name)
+		changed = append(changed, snapshotFlag(flag))
+		if err := resetValue(flag); err != nil {
+			restore()
+			return err
+		}
+	}
+	for _, name := range set {
+		flag := l.flagSet.Lookup(name)
+		changed = append(changed, snapshotFlag(flag))
+		if err := setValue(flag, values[name]); err != nil {
+			restore()
+			return fmt.Errorf("config: invalid value for %q in config file %q: %w", name, fileSources[name], err)
+		}
+	}
+	for _, name := range reset {
+		delete(l.sources, name)
+	}
+	for _, name := range set {
+		l.sources[name] = SourceFile + ":" + fileSources[name]
+	}
+	return nil
+}
+
+func readFile(name string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&values)
+	default:
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: could not decode config file %q: %w", name, err)
+	}
+	return values, nil
+}
+
+func (l *Loader) flatten(key string, value interface{}, out map[string]interface{}) error {
+	key = strings.ReplaceAll(key, "_", "-")
+	if flag := l.flagSet.Lookup(key); flag != nil {
+		out[key] = value
+		return nil
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		for k, v := range m {
+			if err := l.flatten(key+"."+k, v, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown config key %q", key)
+}
+
+func setValue(flag *pflag.Flag, value interface{}) error {
+	switch value := value.(type) {
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, v := range value {
+			values[i] = fmt.Sprint(v)
+		}
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			return sliceValue.Replace(values)
+		}
+		return flag.Value.Set(strings.Join(values, ","))
+	case map[string]interface{}:
+		values := make([]string, 0, len(value))
+		for k, v := range value {
+			values = append(values, fmt.Sprintf("%s=%v", k, v))
+		}
+		sort.Strings(values)
+		return flag.Value.Set(strings.Join(values, ","))
+	case nil:
+		return nil
+	default:
+		return flag.Value.Set(fmt.Sprint(value))
+	}
+}
+
+// scratchFlagSet contains flags of the composite types of pflag, which can not be
+// copied with reflection because their values point to the variables of the flags.
+var scratchFlagSet = map[string]func(*pflag.FlagSet){
+	"boolSlice":      func(fs *pflag.FlagSet) { fs.BoolSlice("v", nil, "") },
+	"durationSlice":  func(fs *pflag.FlagSet) { fs.DurationSlice("v", nil, "") },
+	"float32Slice":   func(fs *pflag.FlagSet) { fs.Float32Slice("v", nil, "") },
+	"float64Slice":   func(fs *pflag.FlagSet) { fs.Float64Slice("v", nil, "") },
+	"intSlice":       func(fs *pflag.FlagSet) { fs.IntSlice("v", nil, "") },
+	"int32Slice":     func(fs *pflag.FlagSet) { fs.Int32Slice("v", nil, "") },
+	"int64Slice":     func(fs *pflag.FlagSet) { fs.Int64Slice("v", nil, "") },
+	"ipSlice":        func(fs *pflag.FlagSet) { fs.IPSlice("v", nil, "") },
+	"stringArray":    func(fs *pflag.FlagSet) { fs.StringArray("v", nil, "") },
+	"stringSlice":    func(fs *pflag.FlagSet) { fs.StringSlice("v", nil, "") },
+	"stringToInt":    func(fs *pflag.FlagSet) { fs.StringToInt("v", nil, "") },
+	"stringToInt64":  func(fs *pflag.FlagSet) { fs.StringToInt64("v", nil, "") },
+	"stringToString": func(fs *pflag.FlagSet) { fs.StringToString("v", nil, "") },
+	"uintSlice":      func(fs *pflag.FlagSet) { fs.UintSlice("v", nil, "") },
+}
+
+// scratchValue returns a new (zero) pflag.Value of the same type as value, which
+// can be used to validate a value without setting it. It returns nil if it does
+// not know how to make a value of that type.
+func scratchValue(value pflag.Value) pflag.Value {
+	if addFlag, ok := scratchFlagSet[value.Type()]; ok {
+		fs := pflag.NewFlagSet("scratch", pflag.ContinueOnError)
+		addFlag(fs)
+		return fs.Lookup("v").Value
+	}
+	// Values of basic types, such as *stringValue, are pointers to the variables of the flags.
+	t := reflect.TypeOf(value)
+	if t.Kind() != reflect.Pointer || t.Elem().Kind() == reflect.Struct {
+		return nil
+	}
+	scratch, ok := reflect.New(t.Elem()).Interface().(pflag.Value)
+	if !ok {
+		return nil
+	}
+	return scratch
+}
+
+// validateValue validates value for the flag by setting it on a scratch value.
+// Values of unknown types are not validated.
+func validateValue(flag *pflag.Flag, value interface{}) error {
+	scratch := scratchValue(flag.Value)
+	if scratch == nil {
+		return nil
+	}
+	return setValue(&pflag.Flag{Name: flag.Name, Value: scratch}, value)
+}
+
+// flagSnapshot is a snapshot of the value of a flag.
+type flagSnapshot struct {
+	flag   *pflag.Flag
+	value  string
+	values []string
+}
+
+func snapshotFlag(flag *pflag.Flag) *flagSnapshot {
+	snapshot := &flagSnapshot{flag: flag}
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		snapshot.values = sliceValue.GetSlice()
+	} else {
+		snapshot.value = flag.Value.String()
+	}
+	return snapshot
+}
+
+func (s *flagSnapshot) restore() {
+	if sliceValue, ok := s.flag.Value.(pflag.SliceValue); ok {
+		sliceValue.Replace(s.values)
+		return
+	}
+	s.flag.Value.Set(s.value)
+}
+
+func resetValue(flag *pflag.Flag) error {
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		var values []string
+		if defValue := strings.Trim(flag.DefValue, "[]"); defValue != "" {
+			values = strings.Split(defValue, ",")
+		}
+		return sliceValue.Replace(values)
+	}
+	return flag.Value.Set(flag.DefValue)
+}
+
+// Source returns the source of the value of the flag with the given name.
+// Values from config files have source "file:" followed by the name of the file.
+func (l *Loader) Source(name string) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if source, ok := l.sources[name]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// VisitAll visits the flags of the FlagSet in lexicographical order, like the VisitAll
+// method of the FlagSet. Reloads do not change values while VisitAll runs, so fn can
+// safely read the values of the flags. fn must not call other methods of the Loader.
+func (l *Loader) VisitAll(fn func(*pflag.Flag)) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.flagSet.VisitAll(fn)
+}
+
+// Sources returns the sources of the values of all flags.
+func (l *Loader) Sources() map[string]string {
+	sources := make(map[string]string)
+	l.flagSet.VisitAll(func(flag *pflag.Flag) {
+		sources[flag.Name] = l.Source(flag.Name)
+	})
+	return sources
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"htdvisser.dev/exp/pflagenv"
+)
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	name = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(name, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestLoader(t *testing.T) {
+	yamlFile := writeFile(t, "config.yml", `
+http:
+  listen: ":8081"
+grpc.listen: ":9091"
+redis_address: "file:6379"
+tags: [a, b]
+`)
+	jsonFile := writeFile(t, "config.json", `{"grpc": {"listen": ":9092"}, "timeout": 5}`)
+
+	t.Setenv("LOADER_TEST_REDIS_ADDRESS", "env:6379")
+	t.Setenv("LOADER_TEST_TIMEOUT", "7")
+
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	httpListen := flagSet.String("http.listen", ":8080", "")
+	grpcListen := flagSet.String("grpc.listen", ":9090", "")
+	redisAddress := flagSet.String("redis-address", "localhost:6379", "")
+	timeout := flagSet.Int("timeout", 1, "")
+	tags := flagSet.StringSlice("tags", nil, "")
+	other := flagSet.String("other", "default", "")
+
+	loader := NewLoader(flagSet, WithEnvOptions(pflagenv.Prefixes("loader_test_")))
+	err := loader.Load([]string{"--config", yamlFile, "--config", jsonFile, "--timeout", "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		value  interface{}
+		want   interface{}
+		source string
+	}{
+		{"http.listen", *httpListen, ":8081", SourceFile + ":" + yamlFile},
+		{"grpc.listen", *grpcListen, ":9092", SourceFile + ":" + jsonFile},
+		{"redis-address", *redisAddress, "env:6379", SourceEnv},
+		{"timeout", *timeout, 10, SourceFlag},
+		{"other", *other, "default", SourceDefault},
+	} {
+		if tt.value != tt.want {
+			t.Errorf("value of %q is %v, want %v", tt.name, tt.value, tt.want)
+		}
+		if source := loader.Source(tt.name); source != tt.source {
+			t.Errorf("source of %q is %q, want %q", tt.name, source, tt.source)
+		}
+	}
+	if len(*tags) != 2 || (*tags)[0] != "a" || (*tags)[1] != "b" {
+		t.Errorf("value of %q is %v, want [a b]", "tags", *tags)
+	}
+
+	// Removing a value from the config file resets it to its default.
+	if err := os.WriteFile(yamlFile, []byte(`grpc.listen: ":9093"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.LoadFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if *httpListen != ":8080" || loader.Source("http.listen") != SourceDefault {
+		t.Errorf("http.listen was not reset to its default: %q (%s)", *httpListen, loader.Source("http.listen"))
+	}
+	if len(*tags) != 0 {
+		t.Errorf("tags were not reset to their default: %v", *tags)
+	}
+}
+
+func TestLoaderUnknownKey(t *testing.T) {
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.String("http.listen", ":8080", "")
+	loader := NewLoader(flagSet)
+	err := loader.Load([]string{"--config", writeFile(t, "config.yml", "http:\n  lisen: \":8081\"\n")})
+	if err == nil {
+		t.Fatal("expected error for unknown config key")
+	}
+}
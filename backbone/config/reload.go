@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"htdvisser.dev/exp/watcher"
+)
+
+// DefaultPollInterval is the default interval for polling config files for changes.
+const DefaultPollInterval = 10 * time.Second
+
+// NewValue returns a watcher.Value that is updated when the config files are reloaded.
+// The get func reads the value from the config (that is bound to the flags with the given names).
+// Only the flags with the given names are updated when the config files are reloaded;
+// changes to other flags require a restart.
+//
+// Components should subscribe to the returned value (with WatchFunc) instead of
+// reading the dynamic settings directly from the config, since those are written
+// during reloads.
+func NewValue[T any](l *Loader, get func() T, equals func(T, T) bool, flagNames ...string) *watcher.Value[T] {
+	value := watcher.NewValue(get(), equals)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, name := range flagNames {
+		l.dynamic[name] = struct{}{}
+	}
+	l.reloaders = append(l.reloaders, func() { value.Set(get()) })
+	return value
+}
+
+// NewComparableValue is like NewValue, but for comparable types.
+func NewComparableValue[T comparable](l *Loader, get func() T, flagNames ...string) *watcher.Value[T] {
+	return NewValue(l, get, func(a, b T) bool { return a == b }, flagNames...)
+}
+
+// Reload reloads the dynamic settings from the config files and notifies the watchers
+// of the values that were returned by NewValue. If the config files can not be read,
+// or contain unknown keys or invalid values, none of the settings are changed.
+func (l *Loader) Reload() error {
+	if err := l.loadFiles(true); err != nil {
+		return err
+	}
+	l.notify()
+	return nil
+}
+
+func (l *Loader) notify() {
+	l.mu.RLock()
+	reloaders := l.reloaders
+	l.mu.RUnlock()
+	for _, reload := range reloaders {
+		reload()
+	}
+}
+
+func (l *Loader) modTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, name := range l.Files() {
+		if info, err := os.Stat(name); err == nil {
+			modTimes[name] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func modTimesEqual(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, t := range a {
+		if !b[name].Equal(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Watch reloads the config files when the process receives SIGHUP, or when
+// the config files change. It polls the config files for changes at the given
+// interval (or DefaultPollInterval if zero). Watch blocks until ctx is done.
+// Errors while reloading are logged.
+func (l *Loader) Watch(ctx context.Context, interval time.Duration) error {
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	modTimes := l.modTimes()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-signals:
+			log.Print("Received SIGHUP, reloading config...")
+		case <-ticker.C:
+			newModTimes := l.modTimes()
+			if modTimesEqual(modTimes, newModTimes) {
+				continue
+			}
+			modTimes = newModTimes
+			log.Print("Config files changed, reloading config...")
+		}
+		if err := l.Reload(); err != nil {
+			log.Printf("Failed to reload config: %v", err)
+		}
+	}
+}
+
+// isDynamic returns whether the flag is updated when reloading.
+func (l *Loader) isDynamic(name string) bool {
+	_, ok := l.dynamic[name]
+	return ok
+}
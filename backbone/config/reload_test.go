@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestReload(t *testing.T) {
+	configFile := writeFile(t, "config.yml", "log-level: info\nhttp.listen: \":8080\"\n")
+
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	logLevel := flagSet.String("log-level", "warn", "")
+	httpListen := flagSet.String("http.listen", ":80", "")
+
+	loader := NewLoader(flagSet)
+	level := NewComparableValue(loader, func() string { return *logLevel }, "log-level")
+
+	var levels []string
+	level.WatchFunc(func(level string) { levels = append(levels, level) })
+
+	if err := loader.Load([]string{"--config", configFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(configFile, []byte("log-level: debug\nhttp.listen: \":8081\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(configFile, []byte("log-level: [invalid\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.Reload(); err == nil {
+		t.Error("expected error when reloading invalid config file")
+	}
+
+	if len(levels) != 3 || levels[0] != "warn" || levels[1] != "info" || levels[2] != "debug" {
+		t.Errorf("log levels were %v, want [warn info debug]", levels)
+	}
+	if *httpListen != ":8080" {
+		t.Errorf("http.listen was changed to %q by reload", *httpListen)
+	}
+}
+
+func TestReloadInvalidValue(t *testing.T) {
+	configFile := writeFile(t, "config.yml", "log-level: info\nports: [80, 443]\n")
+
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	logLevel := flagSet.String("log-level", "warn", "")
+	ports := flagSet.IntSlice("ports", nil, "")
+	debug := flagSet.Bool("debug", false, "")
+
+	loader := NewLoader(flagSet)
+	level := NewComparableValue(loader, func() string { return *logLevel }, "log-level", "ports", "debug")
+
+	var levels []string
+	level.WatchFunc(func(level string) { levels = append(levels, level) })
+
+	if err := loader.Load([]string{"--config", configFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range []string{
+		"log-level: debug\nports: [8080]\ndebug: not-a-bool\n",
+		"log-level: debug\nports: [8080, not-a-port]\ndebug: true\n",
+	} {
+		if err := os.WriteFile(configFile, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := loader.Reload(); err == nil {
+			t.Errorf("expected error when reloading %q", data)
+		}
+		if *logLevel != "info" || len(*ports) != 2 || *debug {
+			t.Errorf("settings were changed to %q, %v and %v by reloading %q", *logLevel, *ports, *debug, data)
+		}
+		if source := loader.Source("log-level"); source != SourceFile+":"+configFile {
+			t.Errorf("source of log-level was changed to %q by reloading %q", source, data)
+		}
+	}
+	if len(levels) != 2 || levels[1] != "info" {
+		t.Errorf("log levels were %v, want [warn info]", levels)
+	}
+}
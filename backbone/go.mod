@@ -2,8 +2,16 @@ module htdvisser.dev/exp/backbone
 
 go 1.20
 
+replace htdvisser.dev/exp/fieldpath => ../fieldpath
+
+replace htdvisser.dev/exp/tlsconfig => ../tlsconfig
+
+replace htdvisser.dev/exp/watcher => ../watcher
+
 require (
+	github.com/alicebob/miniredis/v2 v2.37.0
 	github.com/benbjohnson/clock v1.3.5
+	github.com/go-redis/redis/v8 v8.11.5
 	github.com/gorilla/mux v1.8.1
 	github.com/grpc-ecosystem/go-grpc-middleware v1.4.0
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.18.1
@@ -15,23 +23,34 @@ require (
 	go.opentelemetry.io/otel/trace v1.21.0
 	golang.org/x/net v0.19.0
 	golang.org/x/sync v0.5.0
+	google.golang.org/genproto/googleapis/api v0.0.0-20231212172506-995d672761c0
 	google.golang.org/grpc v1.60.1
+	google.golang.org/protobuf v1.31.0
+	gopkg.in/yaml.v3 v3.0.1
 	htdvisser.dev/exp/clicontext v1.1.0
+	htdvisser.dev/exp/fieldpath v0.0.0-20231206185358-cf15410f4841
 	htdvisser.dev/exp/pflagenv v1.0.0
+	htdvisser.dev/exp/tlsconfig v0.0.0-20231206185358-cf15410f4841
+	htdvisser.dev/exp/watcher v0.0.0-20231206185358-cf15410f4841
 )
 
 require (
 	github.com/cenkalti/backoff/v4 v4.2.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/desertbit/timer v0.0.0-20180107155436-c41aec40b27f // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/go-logr/logr v1.3.0 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/rs/cors v1.10.1 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	github.com/zyedidia/generic v1.2.1 // indirect
+	go.opentelemetry.io/otel/metric v1.21.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 	google.golang.org/genproto v0.0.0-20231212172506-995d672761c0 // indirect
-	google.golang.org/genproto/googleapis/api v0.0.0-20231212172506-995d672761c0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20231212172506-995d672761c0 // indirect
-	google.golang.org/protobuf v1.31.0 // indirect
 	nhooyr.io/websocket v1.8.10 // indirect
 )
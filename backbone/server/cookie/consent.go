@@ -0,0 +1,116 @@
+package cookie
+
+import (
+	"context"
+	"net/http"
+)
+
+// Consent is the consent of a user for device tracking.
+type Consent int
+
+const (
+	// ConsentUnknown means that the user has not given or denied consent.
+	ConsentUnknown Consent = iota
+	// ConsentDenied means that the user has denied consent.
+	ConsentDenied
+	// ConsentGranted means that the user has given consent.
+	ConsentGranted
+)
+
+// String implements fmt.Stringer.
+func (c Consent) String() string {
+	switch c {
+	case ConsentDenied:
+		return "denied"
+	case ConsentGranted:
+		return "granted"
+	default:
+		return "unknown"
+	}
+}
+
+func parseConsent(value string) Consent {
+	switch value {
+	case "granted", "1", "true", "yes":
+		return ConsentGranted
+	case "denied", "0", "false", "no":
+		return ConsentDenied
+	default:
+		return ConsentUnknown
+	}
+}
+
+// PrivacyMode determines how the device UID cookie is used when the user
+// has not given consent.
+type PrivacyMode int
+
+const (
+	// PrivacyModeOff always sets a persistent device UID cookie. This is the default.
+	PrivacyModeOff PrivacyMode = iota
+	// PrivacyModeSession sets a device UID cookie that expires when the browser
+	// is closed, unless the user has given consent.
+	PrivacyModeSession
+	// PrivacyModeStrict doesn't set a device UID cookie (and removes existing ones),
+	// unless the user has given consent.
+	PrivacyModeStrict
+)
+
+// WithPrivacyMode returns an option that sets the privacy mode.
+func WithPrivacyMode(mode PrivacyMode) Option {
+	return optionFunc(func(m *Middleware) {
+		m.privacyMode = mode
+	})
+}
+
+// ConsentHeader is the header that clients can use to communicate consent.
+// Its values are the same as those of the consent cookie.
+const ConsentHeader = "X-Cookie-Consent"
+
+const (
+	consentContextKey         = contextKey("consent")
+	trackingAllowedContextKey = contextKey("trackingAllowed")
+)
+
+// consent returns the consent of the request, which is read from the consent
+// cookie, the ConsentHeader header, or the Sec-GPC (Global Privacy Control) header,
+// in that order.
+func (m *Middleware) consent(r *http.Request) Consent {
+	if consentCookie, err := r.Cookie(m.consentCookieSettings.Name); err == nil {
+		if consent := parseConsent(consentCookie.Value); consent != ConsentUnknown {
+			return consent
+		}
+	}
+	if consent := parseConsent(r.Header.Get(ConsentHeader)); consent != ConsentUnknown {
+		return consent
+	}
+	if r.Header.Get("Sec-GPC") == "1" {
+		return ConsentDenied
+	}
+	return ConsentUnknown
+}
+
+// SetConsent sets (replaces) the consent cookie.
+func (m *Middleware) SetConsent(w http.ResponseWriter, consent Consent) {
+	c := *m.consentCookieSettings
+	c.Value = consent.String()
+	http.SetCookie(w, &c)
+}
+
+// ConsentFromContext returns the consent from a request context.
+// If the DeviceUID middleware was not used, it returns ConsentUnknown.
+func ConsentFromContext(ctx context.Context) Consent {
+	if consent, ok := ctx.Value(consentContextKey).(Consent); ok {
+		return consent
+	}
+	return ConsentUnknown
+}
+
+// TrackingAllowedFromContext returns whether identifiers may be used for tracking
+// (for example in logs and traces) for the request. This is the case when the user
+// has given consent, or when the privacy mode is off.
+func TrackingAllowedFromContext(ctx context.Context) bool {
+	if allowed, ok := ctx.Value(trackingAllowedContextKey).(bool); ok {
+		return allowed
+	}
+	return false
+}
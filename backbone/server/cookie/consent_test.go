@@ -0,0 +1,92 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/segmentio/ksuid"
+)
+
+func TestConsent(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		mode            PrivacyMode
+		header          http.Header
+		consentCookie   string
+		expectedConsent Consent
+		expectedMaxAge  int
+		expectedCookie  bool
+	}{
+		{name: "off", mode: PrivacyModeOff, expectedMaxAge: 60 * 60 * 24 * 180, expectedCookie: true},
+		{name: "session", mode: PrivacyModeSession, expectedCookie: true},
+		{name: "strict", mode: PrivacyModeStrict},
+		{name: "strict with GPC", mode: PrivacyModeStrict, header: http.Header{"Sec-Gpc": {"1"}}, expectedConsent: ConsentDenied},
+		{name: "strict with header", mode: PrivacyModeStrict, header: http.Header{"X-Cookie-Consent": {"granted"}}, expectedConsent: ConsentGranted, expectedMaxAge: 60 * 60 * 24 * 180, expectedCookie: true},
+		{name: "strict with cookie", mode: PrivacyModeStrict, header: http.Header{"Sec-Gpc": {"1"}}, consentCookie: "granted", expectedConsent: ConsentGranted, expectedMaxAge: 60 * 60 * 24 * 180, expectedCookie: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMiddleware(WithPrivacyMode(tc.mode))
+			var (
+				consent Consent
+				duid    ksuid.KSUID
+			)
+			handler := m.DeviceUID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				consent = ConsentFromContext(r.Context())
+				duid = DeviceUIDFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+			if tc.consentCookie != "" {
+				req.AddCookie(&http.Cookie{Name: "consent", Value: tc.consentCookie})
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if consent != tc.expectedConsent {
+				t.Errorf("consent was %s, expected %s", consent, tc.expectedConsent)
+			}
+			cookies := rec.Result().Cookies()
+			if !tc.expectedCookie {
+				if len(cookies) != 0 || duid != ksuid.Nil {
+					t.Errorf("expected no duid, got %v and cookies %v", duid, cookies)
+				}
+				return
+			}
+			if len(cookies) != 1 || cookies[0].MaxAge != tc.expectedMaxAge {
+				t.Errorf("expected duid cookie with max age %d, got %v", tc.expectedMaxAge, cookies)
+			}
+		})
+	}
+}
+
+func TestConsentGrantedLater(t *testing.T) {
+	m := NewMiddleware(WithPrivacyMode(PrivacyModeSession))
+	var duid ksuid.KSUID
+	handler := m.DeviceUID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		duid = DeviceUIDFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge != 0 || !cookies[0].Expires.IsZero() {
+		t.Fatalf("expected session duid cookie, got %v", cookies)
+	}
+	first := duid
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: cookies[0].Name, Value: cookies[0].Value})
+	req.AddCookie(&http.Cookie{Name: "consent", Value: "granted"})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if duid != first {
+		t.Errorf("duid changed from %v to %v after consent", first, duid)
+	}
+	cookies = rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge != 60*60*24*180 {
+		t.Errorf("expected persistent duid cookie after consent, got %v", cookies)
+	}
+}
@@ -4,6 +4,7 @@ package cookie
 import (
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/benbjohnson/clock"
 	"github.com/segmentio/ksuid"
@@ -18,7 +19,15 @@ type Option interface {
 type optionFunc func(*Middleware)
 
 func (f optionFunc) applyTo(opts *Middleware) {
-	f.applyTo(opts)
+	f(opts)
+}
+
+// WithKeyring returns an option that signs (or encrypts) cookie values with the keys
+// in the keyring. Cookies that can not be verified are treated as invalid cookies.
+func WithKeyring(keyring *Keyring) Option {
+	return optionFunc(func(m *Middleware) {
+		m.keyring = keyring
+	})
 }
 
 // NewMiddleware returns new cookie middleware.
@@ -26,10 +35,12 @@ func (f optionFunc) applyTo(opts *Middleware) {
 // Options can be used to override the default options that work with:
 // a "suid" (session) cookie that expires when the browser is closed,
 // a "duid" (device) cookie that expires after 180 days,
-// a "usuid" (user session) cookie.
+// a "usuid" (user session) cookie,
+// a "consent" cookie that expires after 180 days.
 //
 // Note that the cookie middleware doesn't prevent (malicious) clients from
-// sending cookies after they have expired.
+// sending cookies after they have expired. Without the WithKeyring option,
+// the cookie middleware also doesn't prevent clients from forging cookies.
 func NewMiddleware(opts ...Option) *Middleware {
 	m := &Middleware{
 		clock: clock.New(),
@@ -52,6 +63,13 @@ func NewMiddleware(opts ...Option) *Middleware {
 			HttpOnly: true,
 			SameSite: http.SameSiteLaxMode,
 		},
+		consentCookieSettings: &http.Cookie{
+			Name:     "consent",
+			MaxAge:   60 * 60 * 24 * 180,
+			Secure:   false, // allow on HTTP.
+			HttpOnly: false, // allow consent banners to read and set consent.
+			SameSite: http.SameSiteLaxMode,
+		},
 	}
 	for _, opt := range opts {
 		if opt == nil {
@@ -64,10 +82,16 @@ func NewMiddleware(opts ...Option) *Middleware {
 
 // Middleware is cookie middleware. Use NewMiddleware to create a new Middleware.
 type Middleware struct {
-	clock               clock.Clock
-	suidCookieSettings  *http.Cookie
-	duidCookieSettings  *http.Cookie
-	usuidCookieSettings *http.Cookie
+	clock                 clock.Clock
+	keyring               *Keyring
+	privacyMode           PrivacyMode
+	suidCookieSettings    *http.Cookie
+	duidCookieSettings    *http.Cookie
+	usuidCookieSettings   *http.Cookie
+	consentCookieSettings *http.Cookie
+
+	csrfKeyringOnce  sync.Once
+	csrfKeyringValue *Keyring
 }
 
 // MustNewUID returns a new KSUID or panics if it's unable to.
@@ -79,8 +103,26 @@ func (m *Middleware) MustNewUID() ksuid.KSUID {
 	return uid
 }
 
-func (*Middleware) parseUID(uid string) (ksuid.KSUID, error) {
-	return ksuid.Parse(uid)
+func (m *Middleware) encodeUID(name string, uid ksuid.KSUID) string {
+	if m.keyring == nil {
+		return uid.String()
+	}
+	value, err := m.keyring.Encode(name, uid.Bytes())
+	if err != nil {
+		panic(fmt.Errorf("failed to encode cookie value: %w", err))
+	}
+	return value
+}
+
+func (m *Middleware) parseUID(name, value string) (ksuid.KSUID, error) {
+	if m.keyring == nil {
+		return ksuid.Parse(value)
+	}
+	uid, err := m.keyring.Decode(name, value)
+	if err != nil {
+		return ksuid.Nil, err
+	}
+	return ksuid.FromBytes(uid)
 }
 
 type contextKey string
@@ -0,0 +1,131 @@
+package cookie
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/segmentio/ksuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// CSRFHeader is the header that contains the CSRF token.
+	CSRFHeader = "X-CSRF-Token"
+	// CSRFFormField is the form field that contains the CSRF token.
+	CSRFFormField = "csrf_token"
+
+	csrfTokenName       = "csrf"
+	csrfTokenContextKey = contextKey("csrf")
+)
+
+func (m *Middleware) csrfKeyring() *Keyring {
+	if m.keyring != nil {
+		return m.keyring
+	}
+	m.csrfKeyringOnce.Do(func() {
+		secret := make([]byte, MinSecretLength)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Errorf("failed to generate CSRF secret: %w", err))
+		}
+		keyring, err := NewKeyring(false, Key{ID: "csrf", Secret: secret})
+		if err != nil {
+			panic(err)
+		}
+		m.csrfKeyringValue = keyring
+	})
+	return m.csrfKeyringValue
+}
+
+// CSRFToken returns a CSRF token for the session UID.
+func (m *Middleware) CSRFToken(suid ksuid.KSUID) string {
+	token, err := m.csrfKeyring().Encode(csrfTokenName, suid.Bytes())
+	if err != nil {
+		panic(fmt.Errorf("failed to generate CSRF token: %w", err))
+	}
+	return token
+}
+
+func (m *Middleware) validCSRFToken(suid ksuid.KSUID, token string) bool {
+	if token == "" || suid == ksuid.Nil {
+		return false
+	}
+	value, err := m.csrfKeyring().Decode(csrfTokenName, token)
+	if err != nil {
+		return false
+	}
+	uid, err := ksuid.FromBytes(value)
+	return err == nil && uid == suid
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// isCSRFExempt returns true for requests that browsers can not make
+// cross-origin without a CORS preflight, and for requests that don't use cookies.
+func isCSRFExempt(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		return true // gRPC and gRPC-Web.
+	}
+	if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		return true
+	}
+	return false
+}
+
+// CSRF is a middleware that protects against cross-site request forgery.
+// It requires a CSRF token that is bound to the session UID in the CSRFHeader
+// header or CSRFFormField form field of requests with unsafe methods, and rejects
+// requests without a valid token. Requests with gRPC or gRPC-Web content types
+// and requests with bearer tokens are exempt.
+//
+// The CSRF middleware must be added after the SessionUID middleware. Without the
+// WithKeyring option, the CSRF tokens are only valid within the current process.
+func (m *Middleware) CSRF(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suid := SessionUIDFromContext(r.Context())
+		if !isSafeMethod(r.Method) && !isCSRFExempt(r) {
+			token := r.Header.Get(CSRFHeader)
+			if token == "" {
+				token = r.PostFormValue(CSRFFormField)
+			}
+			if !m.validCSRFToken(suid, token) {
+				trace.SpanFromContext(r.Context()).AddEvent("reject request with invalid csrf token")
+				http.Error(w, "invalid CSRF token", http.StatusForbidden)
+				return
+			}
+		}
+		var token string
+		if suid != ksuid.Nil {
+			token = m.CSRFToken(suid)
+		}
+		ctx := context.WithValue(r.Context(), csrfTokenContextKey, token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// CSRFTokenFromContext returns the CSRF token from a request context,
+// or returns an empty string if there is no CSRF token present.
+func CSRFTokenFromContext(ctx context.Context) string {
+	if token, ok := ctx.Value(csrfTokenContextKey).(string); ok {
+		return token
+	}
+	return ""
+}
+
+// CSRFField returns a hidden form field with the CSRF token from a request context,
+// to be embedded in HTML templates.
+func CSRFField(ctx context.Context) template.HTML {
+	return template.HTML(fmt.Sprintf(
+		`<input type="hidden" name="%s" value="%s">`,
+		CSRFFormField, template.HTMLEscapeString(CSRFTokenFromContext(ctx)),
+	))
+}
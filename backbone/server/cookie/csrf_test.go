@@ -0,0 +1,63 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRF(t *testing.T) {
+	m := NewMiddleware()
+
+	var token string
+	handler := m.SessionUID(m.CSRF(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = CSRFTokenFromContext(r.Context())
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || token == "" {
+		t.Fatalf("GET request returned %d with token %q", rec.Code, token)
+	}
+	suid := rec.Result().Cookies()[0]
+
+	for _, tc := range []struct {
+		name     string
+		header   http.Header
+		form     url.Values
+		expected int
+	}{
+		{name: "no token", expected: http.StatusForbidden},
+		{name: "invalid token", header: http.Header{CSRFHeader: {"invalid"}}, expected: http.StatusForbidden},
+		{name: "header token", header: http.Header{CSRFHeader: {token}}, expected: http.StatusOK},
+		{name: "form token", form: url.Values{CSRFFormField: {token}}, expected: http.StatusOK},
+		{name: "gRPC-Web", header: http.Header{"Content-Type": {"application/grpc-web+proto"}}, expected: http.StatusOK},
+		{name: "bearer token", header: http.Header{"Authorization": {"Bearer secret"}}, expected: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.form.Encode()))
+			for key, values := range tc.header {
+				req.Header.Set(key, values[0])
+			}
+			if tc.form != nil {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			req.AddCookie(suid)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.expected {
+				t.Errorf("POST request returned %d, expected %d", rec.Code, tc.expected)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(CSRFHeader, token)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("POST request with token of another session returned %d, expected 403", rec.Code)
+	}
+}
@@ -11,43 +11,88 @@ import (
 )
 
 const (
-	duidAttributeKey = attribute.Key("duid")
-	duidContextKey   = contextKey("duid")
+	duidAttributeKey    = attribute.Key("duid")
+	duidContextKey      = contextKey("duid")
+	consentAttributeKey = attribute.Key("consent")
 )
 
 // SetDeviceUID sets (replaces) the user device UID cookie.
 func (m *Middleware) SetDeviceUID(w http.ResponseWriter, value ksuid.KSUID) {
 	c := *m.duidCookieSettings
-	c.Value = value.String()
+	c.Value = m.encodeUID(c.Name, value)
+	http.SetCookie(w, &c)
+}
+
+func (m *Middleware) setSessionDeviceUID(w http.ResponseWriter, value ksuid.KSUID) {
+	c := *m.duidCookieSettings
+	c.Value = m.encodeUID(c.Name, value)
+	c.MaxAge = 0
+	http.SetCookie(w, &c)
+}
+
+func (m *Middleware) unsetDeviceUID(w http.ResponseWriter) {
+	c := *m.duidCookieSettings
+	c.MaxAge = -1
 	http.SetCookie(w, &c)
 }
 
 // DeviceUID is a middleware that reads device UID cookies from requests,
 // or generates a new device UID.
+//
+// The DeviceUID middleware also reads the consent of the user (see ConsentFromContext).
+// Depending on the privacy mode, a device UID cookie that expires when the browser
+// is closed, or no device UID cookie is used when the user has not given consent.
+// When the user gives consent, the existing device UID is kept in a persistent cookie.
 func (m *Middleware) DeviceUID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		span := trace.SpanFromContext(r.Context())
+		consent := m.consent(r)
+		trackingAllowed := consent == ConsentGranted || m.privacyMode == PrivacyModeOff
+		span.SetAttributes(consentAttributeKey.String(consent.String()))
+		ctx := context.WithValue(r.Context(), consentContextKey, consent)
+		ctx = context.WithValue(ctx, trackingAllowedContextKey, trackingAllowed)
+		setDeviceUID := m.SetDeviceUID
+		if !trackingAllowed {
+			if m.privacyMode == PrivacyModeStrict {
+				if _, err := r.Cookie(m.duidCookieSettings.Name); err == nil {
+					span.AddEvent("unset duid cookie without consent")
+					m.unsetDeviceUID(w)
+				}
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			setDeviceUID = m.setSessionDeviceUID
+		}
 		duidCookie, err := r.Cookie(m.duidCookieSettings.Name)
 		var uid ksuid.KSUID
 		switch {
 		case errors.Is(err, http.ErrNoCookie):
 			span.AddEvent("set new duid cookie")
 			uid = m.MustNewUID()
-			m.SetDeviceUID(w, uid)
+			setDeviceUID(w, uid)
 		case err != nil:
 			span.RecordError(err)
 			uid = m.MustNewUID()
-			m.SetDeviceUID(w, uid)
+			setDeviceUID(w, uid)
 		default:
-			uid, err = m.parseUID(duidCookie.Value)
+			uid, err = m.parseUID(duidCookie.Name, duidCookie.Value)
 			if err != nil {
 				span.AddEvent("replace invalid duid cookie")
 				uid = m.MustNewUID()
-				m.SetDeviceUID(w, uid)
+				setDeviceUID(w, uid)
+			} else if !trackingAllowed {
+				setDeviceUID(w, uid) // Make sure that the cookie expires when the browser is closed.
+			} else if m.privacyMode == PrivacyModeSession {
+				// The cookie may have been set to expire when the browser is closed,
+				// before the user gave consent. We can't see that in the request,
+				// so we make the cookie persistent.
+				setDeviceUID(w, uid)
 			}
 		}
-		span.SetAttributes(duidAttributeKey.String(uid.String()))
-		ctx := context.WithValue(r.Context(), duidContextKey, uid)
+		if trackingAllowed {
+			span.SetAttributes(duidAttributeKey.String(uid.String()))
+		}
+		ctx = context.WithValue(ctx, duidContextKey, uid)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
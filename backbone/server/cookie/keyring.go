@@ -0,0 +1,152 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MinSecretLength is the minimum length of key secrets.
+const MinSecretLength = 32
+
+// Key is a key that is used to sign (and encrypt) cookie values.
+type Key struct {
+	// ID identifies the key. It is included in cookie values,
+	// so that values signed with older keys can still be verified.
+	ID string
+	// Secret is the secret of the key. It must be at least MinSecretLength bytes long.
+	Secret []byte
+}
+
+type keyringKey struct {
+	signingKey    []byte
+	encryptionKey cipher.AEAD
+}
+
+// Keyring is a set of keys that is used to sign (and encrypt) cookie values.
+// The first key is used for signing new values, all keys are used for verifying values.
+// To rotate keys, add a new key to the front of the keyring, and remove the old
+// key once all cookies that were signed with it have expired.
+type Keyring struct {
+	encrypt bool
+	keyID   string
+	keys    map[string]*keyringKey
+}
+
+// ErrInvalidValue is returned when a cookie value can not be verified.
+var ErrInvalidValue = errors.New("invalid cookie value")
+
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// NewKeyring returns a new keyring with the given keys. If encrypt is true, cookie
+// values are encrypted with AES-GCM. Otherwise they are signed with HMAC-SHA256.
+func NewKeyring(encrypt bool, keys ...Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring needs at least one key")
+	}
+	k := &Keyring{
+		encrypt: encrypt,
+		keyID:   keys[0].ID,
+		keys:    make(map[string]*keyringKey, len(keys)),
+	}
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ".") {
+			return nil, fmt.Errorf("invalid key ID %q", key.ID)
+		}
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key ID %q", key.ID)
+		}
+		if len(key.Secret) < MinSecretLength {
+			return nil, fmt.Errorf("secret of key %q is shorter than %d bytes", key.ID, MinSecretLength)
+		}
+		block, err := aes.NewCipher(deriveKey(key.Secret, "encrypt"))
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[key.ID] = &keyringKey{
+			signingKey:    deriveKey(key.Secret, "sign"),
+			encryptionKey: aead,
+		}
+	}
+	return k, nil
+}
+
+func (k *keyringKey) sign(name, keyID string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, k.signingKey)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(keyID))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+var encoding = base64.RawURLEncoding
+
+// Encode signs (or encrypts) the value of the cookie with the given name.
+// The name is included in the signature, so that values can not be moved to other cookies.
+func (k *Keyring) Encode(name string, value []byte) (string, error) {
+	key := k.keys[k.keyID]
+	if k.encrypt {
+		nonce := make([]byte, key.encryptionKey.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		sealed := key.encryptionKey.Seal(nonce, nonce, value, []byte(name+"\x00"+k.keyID))
+		return k.keyID + "." + encoding.EncodeToString(sealed), nil
+	}
+	return k.keyID + "." + encoding.EncodeToString(value) + "." + encoding.EncodeToString(key.sign(name, k.keyID, value)), nil
+}
+
+// Decode verifies (or decrypts) the value of the cookie with the given name.
+func (k *Keyring) Decode(name, value string) ([]byte, error) {
+	parts := strings.Split(value, ".")
+	key, ok := k.keys[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key", ErrInvalidValue)
+	}
+	if k.encrypt {
+		if len(parts) != 2 {
+			return nil, ErrInvalidValue
+		}
+		sealed, err := encoding.DecodeString(parts[1])
+		if err != nil || len(sealed) < key.encryptionKey.NonceSize() {
+			return nil, ErrInvalidValue
+		}
+		nonce, ciphertext := sealed[:key.encryptionKey.NonceSize()], sealed[key.encryptionKey.NonceSize():]
+		payload, err := key.encryptionKey.Open(nil, nonce, ciphertext, []byte(name+"\x00"+parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidValue, err)
+		}
+		return payload, nil
+	}
+	if len(parts) != 3 {
+		return nil, ErrInvalidValue
+	}
+	payload, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidValue
+	}
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidValue
+	}
+	if !hmac.Equal(signature, key.sign(name, parts[0], payload)) {
+		return nil, fmt.Errorf("%w: invalid signature", ErrInvalidValue)
+	}
+	return payload, nil
+}
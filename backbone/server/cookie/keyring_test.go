@@ -0,0 +1,89 @@
+package cookie
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	oldKey := Key{ID: "old", Secret: bytes.Repeat([]byte{1}, MinSecretLength)}
+	newKey := Key{ID: "new", Secret: bytes.Repeat([]byte{2}, MinSecretLength)}
+
+	for _, encrypt := range []bool{false, true} {
+		oldKeyring, err := NewKeyring(encrypt, oldKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rotatedKeyring, err := NewKeyring(encrypt, newKey, oldKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		value, err := oldKeyring.Encode("suid", []byte("value"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encrypt && bytes.Contains([]byte(value), []byte("dmFsdWU")) {
+			t.Errorf("encrypted value %q contains the plaintext", value)
+		}
+		if decoded, err := rotatedKeyring.Decode("suid", value); err != nil || string(decoded) != "value" {
+			t.Errorf("rotated keyring decoded %q, %v, expected value", decoded, err)
+		}
+		if _, err := rotatedKeyring.Decode("usuid", value); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("decoding value of another cookie returned %v, expected ErrInvalidValue", err)
+		}
+		if _, err := rotatedKeyring.Decode("suid", value+"x"); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("decoding tampered value returned %v, expected ErrInvalidValue", err)
+		}
+
+		value, err = rotatedKeyring.Encode("suid", []byte("value"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := oldKeyring.Decode("suid", value); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("decoding value with unknown key returned %v, expected ErrInvalidValue", err)
+		}
+	}
+
+	if _, err := NewKeyring(false, Key{ID: "short", Secret: []byte("short")}); err == nil {
+		t.Error("expected error for short secret")
+	}
+}
+
+func TestForgedDeviceUID(t *testing.T) {
+	keyring, err := NewKeyring(false, Key{ID: "1", Secret: bytes.Repeat([]byte{1}, MinSecretLength)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMiddleware(WithKeyring(keyring))
+	forged := m.MustNewUID()
+
+	var seen string
+	handler := m.DeviceUID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = DeviceUIDFromContext(r.Context()).String()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "duid", Value: forged.String()})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen == forged.String() {
+		t.Fatal("forged device UID was accepted")
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "duid" {
+		t.Fatalf("expected replaced duid cookie, got %v", cookies)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("signed duid cookie was replaced")
+	}
+}
@@ -17,7 +17,7 @@ const (
 
 func (m *Middleware) setSessionUID(w http.ResponseWriter, value ksuid.KSUID) {
 	c := *m.suidCookieSettings
-	c.Value = value.String()
+	c.Value = m.encodeUID(c.Name, value)
 	http.SetCookie(w, &c)
 }
 
@@ -38,7 +38,7 @@ func (m *Middleware) SessionUID(next http.Handler) http.Handler {
 			uid = m.MustNewUID()
 			m.setSessionUID(w, uid)
 		default:
-			uid, err = m.parseUID(suidCookie.Value)
+			uid, err = m.parseUID(suidCookie.Name, suidCookie.Value)
 			if err != nil {
 				span.AddEvent("replace invalid suid cookie")
 				uid = m.MustNewUID()
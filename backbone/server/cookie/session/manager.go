@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/segmentio/ksuid"
+	"go.opentelemetry.io/otel/trace"
+	"htdvisser.dev/exp/backbone/server"
+	"htdvisser.dev/exp/backbone/server/cookie"
+)
+
+// Manager manages user sessions. It sets the user session UID cookie on login,
+// loads sessions for requests, and extends their expiry (sliding expiry).
+type Manager struct {
+	clock   clock.Clock
+	cookies *cookie.Middleware
+	store   Store
+	maxAge  int
+}
+
+// NewManager returns a new session manager. Sessions and the user session
+// UID cookie expire after maxAge seconds of inactivity.
+func NewManager(cookies *cookie.Middleware, store Store, maxAge int, opts ...Option) *Manager {
+	options := newOptions(opts...)
+	return &Manager{
+		clock:   options.clock,
+		cookies: cookies,
+		store:   store,
+		maxAge:  maxAge,
+	}
+}
+
+func (m *Manager) maxAgeDuration() time.Duration {
+	return time.Duration(m.maxAge) * time.Second
+}
+
+// Login starts a new session for the user, and sets the user session UID cookie.
+func (m *Manager) Login(ctx context.Context, w http.ResponseWriter, userID string, data map[string]string) (*Session, error) {
+	session := &Session{
+		ID:        m.cookies.MustNewUID(),
+		UserID:    userID,
+		Data:      data,
+		CreatedAt: m.clock.Now(),
+	}
+	if err := m.store.Save(ctx, session, m.maxAgeDuration()); err != nil {
+		return nil, err
+	}
+	m.cookies.SetUserSessionUID(w, session.ID, m.maxAge)
+	return session, nil
+}
+
+// Save saves changes to the data of the session.
+func (m *Manager) Save(ctx context.Context, session *Session) error {
+	return m.store.Save(ctx, session, m.maxAgeDuration())
+}
+
+// Logout revokes the session in the context, and unsets the user session UID cookie.
+func (m *Manager) Logout(ctx context.Context, w http.ResponseWriter) error {
+	m.cookies.UnsetUserSessionUID(w)
+	if uid := cookie.UserSessionUIDFromContext(ctx); uid != ksuid.Nil {
+		return m.store.Revoke(ctx, uid)
+	}
+	return nil
+}
+
+// LogoutEverywhere revokes all sessions of the user, and unsets the user session UID cookie.
+func (m *Manager) LogoutEverywhere(ctx context.Context, w http.ResponseWriter, userID string) error {
+	m.cookies.UnsetUserSessionUID(w)
+	return m.store.RevokeUser(ctx, userID)
+}
+
+// Middleware is a middleware that loads the session for the user session UID of
+// the request, and extends the expiry of the session and the user session UID cookie.
+// If the session does not exist (anymore), the user session UID cookie is unset.
+// It must be added after the cookie middleware.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uid := cookie.UserSessionUIDFromContext(r.Context())
+		if uid == ksuid.Nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		session, err := m.store.Load(r.Context(), uid)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			span.AddEvent("unset usuid cookie of unknown session")
+			m.cookies.UnsetUserSessionUID(w)
+			next.ServeHTTP(w, r)
+			return
+		case err != nil:
+			span.RecordError(err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := m.store.Touch(r.Context(), uid, m.maxAgeDuration()); err != nil {
+			span.RecordError(err)
+		} else {
+			m.cookies.SetUserSessionUID(w, uid, m.maxAge)
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), session)))
+	})
+}
+
+// Register registers the middleware to the (public) HTTP server of a backbone server.
+// The cookie middleware must be registered first. The middleware is not registered
+// to the internal HTTP server, because the internal endpoints are not used by browsers
+// and should not extend user sessions.
+func (m *Manager) Register(s *server.Server) {
+	s.HTTP.AddMiddleware(m.Middleware)
+}
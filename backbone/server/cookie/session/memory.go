@@ -0,0 +1,124 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/segmentio/ksuid"
+)
+
+// MemoryStore is an in-memory Store. It is useful for development and testing,
+// but sessions are lost when the process exits and are not shared between processes.
+//
+// Expired sessions are deleted when they are loaded or touched, and by a sweep of
+// all sessions when sessions are saved, at most once per sweepInterval.
+type MemoryStore struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	sessions  map[ksuid.KSUID]*Session
+	nextSweep time.Time
+}
+
+const sweepInterval = time.Minute
+
+// NewMemoryStore returns a new in-memory Store.
+func NewMemoryStore(opts ...Option) *MemoryStore {
+	options := newOptions(opts...)
+	return &MemoryStore{
+		clock:    options.clock,
+		sessions: make(map[ksuid.KSUID]*Session),
+	}
+}
+
+// sweep deletes expired sessions if the last sweep was more than sweepInterval ago.
+func (s *MemoryStore) sweep() {
+	now := s.clock.Now()
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(sweepInterval)
+	for id, session := range s.sessions {
+		if !now.Before(session.ExpiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func copySession(session *Session) *Session {
+	cpy := *session
+	if session.Data != nil {
+		cpy.Data = make(map[string]string, len(session.Data))
+		for k, v := range session.Data {
+			cpy.Data[k] = v
+		}
+	}
+	return &cpy
+}
+
+func (s *MemoryStore) get(id ksuid.KSUID) (*Session, error) {
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !s.clock.Now().Before(session.ExpiresAt) {
+		delete(s.sessions, id)
+		return nil, ErrNotFound
+	}
+	return session, nil
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(_ context.Context, id ksuid.KSUID) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return copySession(session), nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, session *Session, maxAge time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+	session.ExpiresAt = s.clock.Now().Add(maxAge)
+	s.sessions[session.ID] = copySession(session)
+	return nil
+}
+
+// Touch implements Store.
+func (s *MemoryStore) Touch(_ context.Context, id ksuid.KSUID, maxAge time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	session.ExpiresAt = s.clock.Now().Add(maxAge)
+	return nil
+}
+
+// Revoke implements Store.
+func (s *MemoryStore) Revoke(_ context.Context, id ksuid.KSUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// RevokeUser implements Store.
+func (s *MemoryStore) RevokeUser(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, session := range s.sessions {
+		if session.UserID == userID {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/segmentio/ksuid"
+)
+
+// RedisStore is a Store that stores sessions in Redis.
+//
+// Each session is stored as JSON under "<prefix>session:<id>" with the
+// session's expiry as TTL. The IDs of the sessions of each user are kept in a
+// set under "<prefix>user:<user id>", so that they can be revoked together.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore returns a new Store on top of the given Redis client,
+// which is typically obtained from a redisconfig.Config.
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisStore) sessionKey(id ksuid.KSUID) string {
+	return s.prefix + "session:" + id.String()
+}
+
+func (s *RedisStore) userKey(userID string) string {
+	return s.prefix + "user:" + userID
+}
+
+// Load implements Store.
+func (s *RedisStore) Load(ctx context.Context, id ksuid.KSUID) (*Session, error) {
+	var (
+		get  *redis.StringCmd
+		pttl *redis.DurationCmd
+	)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, s.sessionKey(id))
+		pttl = pipe.PTTL(ctx, s.sessionKey(id))
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal([]byte(get.Val()), &session); err != nil {
+		return nil, err
+	}
+	// Touch only extends the TTL, so the expiry is taken from the TTL.
+	if ttl := pttl.Val(); ttl > 0 {
+		session.ExpiresAt = time.Now().Add(ttl)
+	}
+	return &session, nil
+}
+
+// Save implements Store.
+func (s *RedisStore) Save(ctx context.Context, session *Session, maxAge time.Duration) error {
+	session.ExpiresAt = time.Now().Add(maxAge)
+	b, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.sessionKey(session.ID), b, maxAge)
+		if session.UserID != "" {
+			pipe.SAdd(ctx, s.userKey(session.UserID), session.ID.String())
+			pipe.Expire(ctx, s.userKey(session.UserID), maxAge)
+		}
+		return nil
+	})
+	return err
+}
+
+// Touch implements Store.
+//
+// The session is only loaded to find its user. Touch then only extends the TTL
+// of the session and of the set of sessions of the user. It does not write the
+// session, so that it does not bring back a session that is revoked concurrently,
+// or overwrite changes that are saved concurrently.
+func (s *RedisStore) Touch(ctx context.Context, id ksuid.KSUID, maxAge time.Duration) error {
+	session, err := s.Load(ctx, id)
+	if err != nil {
+		return err
+	}
+	var expire *redis.BoolCmd
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		expire = pipe.PExpire(ctx, s.sessionKey(id), maxAge)
+		if session.UserID != "" {
+			pipe.PExpire(ctx, s.userKey(session.UserID), maxAge)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !expire.Val() {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Revoke implements Store.
+func (s *RedisStore) Revoke(ctx context.Context, id ksuid.KSUID) error {
+	session, err := s.Load(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.sessionKey(id))
+		if session.UserID != "" {
+			pipe.SRem(ctx, s.userKey(session.UserID), id.String())
+		}
+		return nil
+	})
+	return err
+}
+
+// RevokeUser implements Store.
+func (s *RedisStore) RevokeUser(ctx context.Context, userID string) error {
+	ids, err := s.client.SMembers(ctx, s.userKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{s.userKey(userID)}
+	for _, id := range ids {
+		keys = append(keys, s.prefix+"session:"+id)
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"htdvisser.dev/exp/backbone/server/cookie"
+)
+
+// revokeBeforeExpire is a redis.Hook that revokes a session right before
+// the TTL of the session is extended.
+type revokeBeforeExpire struct {
+	key    string
+	revoke func(ctx context.Context) error
+}
+
+func (h *revokeBeforeExpire) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *revokeBeforeExpire) AfterProcess(context.Context, redis.Cmder) error { return nil }
+
+func (h *revokeBeforeExpire) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	for _, cmd := range cmds {
+		if args := cmd.Args(); cmd.Name() == "pexpire" && args[1] == h.key && h.revoke != nil {
+			revoke := h.revoke
+			h.revoke = nil
+			return ctx, revoke(ctx)
+		}
+	}
+	return ctx, nil
+}
+
+func (h *revokeBeforeExpire) AfterProcessPipeline(context.Context, []redis.Cmder) error { return nil }
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	store := NewRedisStore(client, "test:")
+	cookies := cookie.NewMiddleware()
+
+	session := &Session{ID: cookies.MustNewUID(), UserID: "alice", Data: map[string]string{"theme": "light"}}
+	if err := store.Save(ctx, session, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another request changes the session before it is touched.
+	changed := *session
+	changed.Data = map[string]string{"theme": "dark"}
+	if err := store.Save(ctx, &changed, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Touch(ctx, session.ID, 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.Load(ctx, session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme := loaded.Data["theme"]; theme != "dark" {
+		t.Errorf("touched session has theme %q, expected dark", theme)
+	}
+	if ttl := mr.TTL(store.sessionKey(session.ID)); ttl != 2*time.Minute {
+		t.Errorf("touched session has TTL %s, expected 2m0s", ttl)
+	}
+
+	// Another request revokes the session while it is touched.
+	revokeClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer revokeClient.Close()
+	client.AddHook(&revokeBeforeExpire{
+		key: store.sessionKey(session.ID),
+		revoke: func(ctx context.Context) error {
+			return NewRedisStore(revokeClient, "test:").Revoke(ctx, session.ID)
+		},
+	})
+	if err := store.Touch(ctx, session.ID, time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Errorf("touching revoked session returned %v, expected ErrNotFound", err)
+	}
+	if _, err := store.Load(ctx, session.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("revoked session returned %v, expected ErrNotFound", err)
+	}
+	if isMember, err := client.SIsMember(ctx, store.userKey("alice"), session.ID.String()).Result(); err != nil {
+		t.Fatal(err)
+	} else if isMember {
+		t.Error("revoked session was added back to the sessions of the user")
+	}
+
+	if err := store.Touch(ctx, cookies.MustNewUID(), time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Errorf("touching unknown session returned %v, expected ErrNotFound", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package session provides server-side storage of user sessions that are
+// identified by the user session UID cookie of the cookie middleware.
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/segmentio/ksuid"
+)
+
+// Session is a user session.
+type Session struct {
+	ID        ksuid.KSUID       `json:"id"`
+	UserID    string            `json:"user_id"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ErrNotFound is returned when a session is not found or has expired.
+var ErrNotFound = errors.New("session not found")
+
+// Store stores sessions.
+type Store interface {
+	// Load loads the session with the given ID.
+	// If the session does not exist or has expired, Load returns ErrNotFound.
+	Load(ctx context.Context, id ksuid.KSUID) (*Session, error)
+	// Save saves the session. The session expires after maxAge.
+	Save(ctx context.Context, session *Session, maxAge time.Duration) error
+	// Touch extends the expiry of the session with the given ID to maxAge from now.
+	// If the session does not exist or has expired, Touch returns ErrNotFound.
+	Touch(ctx context.Context, id ksuid.KSUID, maxAge time.Duration) error
+	// Revoke deletes the session with the given ID.
+	Revoke(ctx context.Context, id ksuid.KSUID) error
+	// RevokeUser deletes all sessions of the user with the given ID.
+	RevokeUser(ctx context.Context, userID string) error
+}
+
+type options struct {
+	clock clock.Clock
+}
+
+func newOptions(opts ...Option) *options {
+	options := &options{
+		clock: clock.New(),
+	}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	return options
+}
+
+// Option is an option for the Manager or MemoryStore.
+type Option interface {
+	apply(*options)
+}
+
+type option func(*options)
+
+func (f option) apply(opts *options) {
+	f(opts)
+}
+
+// WithClock returns an option that sets the clock.
+func WithClock(clock clock.Clock) Option {
+	return option(func(o *options) {
+		o.clock = clock
+	})
+}
+
+type contextKeyType struct{}
+
+var contextKey contextKeyType
+
+// NewContext returns a context derived from parent that contains the session.
+func NewContext(parent context.Context, session *Session) context.Context {
+	return context.WithValue(parent, contextKey, session)
+}
+
+// FromContext returns the session from the context, or nil if there is no session.
+func FromContext(ctx context.Context) *Session {
+	if session, ok := ctx.Value(contextKey).(*Session); ok {
+		return session
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"htdvisser.dev/exp/backbone/server/cookie"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	store := NewMemoryStore(WithClock(clock))
+	cookies := cookie.NewMiddleware()
+
+	first := &Session{ID: cookies.MustNewUID(), UserID: "alice"}
+	second := &Session{ID: cookies.MustNewUID(), UserID: "alice"}
+	for _, session := range []*Session{first, second} {
+		if err := store.Save(ctx, session, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clock.Add(30 * time.Second)
+	if err := store.Touch(ctx, first.ID, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	clock.Add(45 * time.Second)
+	if _, err := store.Load(ctx, first.ID); err != nil {
+		t.Errorf("touched session was not loaded: %v", err)
+	}
+	if _, err := store.Load(ctx, second.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expired session returned %v, expected ErrNotFound", err)
+	}
+
+	// Sessions that expire without being loaded again are swept on save.
+	third := &Session{ID: cookies.MustNewUID(), UserID: "bob"}
+	if err := store.Save(ctx, third, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	clock.Add(2 * time.Minute)
+	if err := store.Save(ctx, &Session{ID: cookies.MustNewUID(), UserID: "bob"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	store.mu.Lock()
+	_, ok := store.sessions[third.ID]
+	store.mu.Unlock()
+	if ok {
+		t.Error("expired session was not swept")
+	}
+
+	if err := store.RevokeUser(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(ctx, first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("revoked session returned %v, expected ErrNotFound", err)
+	}
+}
+
+func TestManager(t *testing.T) {
+	cookies := cookie.NewMiddleware()
+	store := NewMemoryStore()
+	manager := NewManager(cookies, store, 3600)
+
+	var loaded *Session
+	handler := cookies.UserSessionUID(manager.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loaded = FromContext(r.Context())
+		if r.URL.Path == "/logout" {
+			if err := manager.Logout(r.Context(), w); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})))
+
+	rec := httptest.NewRecorder()
+	session, err := manager.Login(context.Background(), rec, "alice", map[string]string{"name": "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	usuid := rec.Result().Cookies()[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(usuid)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if loaded == nil || loaded.ID != session.ID || loaded.Data["name"] != "Alice" {
+		t.Fatalf("session was not loaded: %v", loaded)
+	}
+	if cookies := rec.Result().Cookies(); len(cookies) != 1 || cookies[0].MaxAge != 3600 {
+		t.Errorf("usuid cookie was not extended: %v", cookies)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/logout", nil)
+	req.AddCookie(usuid)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(usuid)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if loaded != nil {
+		t.Errorf("session was loaded after logout: %v", loaded)
+	}
+	if cookies := rec.Result().Cookies(); len(cookies) != 1 || cookies[0].MaxAge != -1 {
+		t.Errorf("usuid cookie was not unset: %v", cookies)
+	}
+}
@@ -18,7 +18,7 @@ const (
 // SetUserSessionUID sets the user session UID cookie, with expiry after maxAge.
 func (m *Middleware) SetUserSessionUID(w http.ResponseWriter, value ksuid.KSUID, maxAge int) {
 	c := *m.usuidCookieSettings
-	c.Value = value.String()
+	c.Value = m.encodeUID(c.Name, value)
 	c.MaxAge = maxAge
 	http.SetCookie(w, &c)
 }
@@ -43,7 +43,7 @@ func (m *Middleware) UserSessionUID(next http.Handler) http.Handler {
 		case err != nil:
 			span.RecordError(err)
 		default:
-			uid, err = m.parseUID(usuidCookie.Value)
+			uid, err = m.parseUID(usuidCookie.Name, usuidCookie.Value)
 			if err != nil {
 				span.AddEvent("ignore invalid usuid cookie")
 				uid = ksuid.Nil
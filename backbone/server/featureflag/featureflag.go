@@ -0,0 +1,224 @@
+// Package featureflag provides feature flags that are evaluated against the request context.
+//
+// Flags can be enabled for a percentage of subjects (devices, users or principals),
+// and for allow and deny lists of subjects. The percentage rollout uses stable hashing,
+// so that a subject keeps getting the same result as long as the percentage is not lowered.
+package featureflag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	"github.com/segmentio/ksuid"
+	"htdvisser.dev/exp/backbone/server/cookie"
+	"htdvisser.dev/exp/backbone/server/cookie/session"
+)
+
+// Flag is a feature flag.
+type Flag struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Enabled indicates if the flag is enabled at all.
+	// If false, the flag is disabled for all subjects.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Percentage is the percentage (0-100) of subjects for which the flag is enabled.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+	// Allow contains subjects (device UIDs, user UIDs or principals) for which the flag is always enabled.
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	// Deny contains subjects (device UIDs, user UIDs or principals) for which the flag is always disabled.
+	Deny []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+// Override is an override of a flag that is set at runtime.
+type Override int
+
+// Overrides.
+const (
+	OverrideNone Override = iota
+	OverrideOn
+	OverrideOff
+)
+
+// Subject is the subject for which flags are evaluated.
+type Subject struct {
+	DeviceUID string
+	UserUID   string
+	Principal string
+}
+
+// key returns the key of the subject that is used for percentage rollouts.
+// Principals and users take priority over devices, so that a user gets the same
+// result on all of their devices.
+func (s Subject) key() string {
+	switch {
+	case s.Principal != "":
+		return "principal:" + s.Principal
+	case s.UserUID != "":
+		return "user:" + s.UserUID
+	case s.DeviceUID != "":
+		return "device:" + s.DeviceUID
+	default:
+		return ""
+	}
+}
+
+func (s Subject) in(list []string) bool {
+	for _, item := range list {
+		if item == "" {
+			continue
+		}
+		if item == s.DeviceUID || item == s.UserUID || item == s.Principal {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKeyType struct{}
+
+var principalKey principalKeyType
+
+// NewContextWithPrincipal returns a context derived from parent that contains the
+// principal (such as an API key ID or service account) that flags are evaluated for.
+func NewContextWithPrincipal(parent context.Context, principal string) context.Context {
+	return context.WithValue(parent, principalKey, principal)
+}
+
+// PrincipalFromContext returns the principal from the context.
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey).(string)
+	return principal
+}
+
+// SubjectFromContext returns the subject from the device UID cookie,
+// the user session and the principal in the context.
+func SubjectFromContext(ctx context.Context) Subject {
+	var subject Subject
+	if uid := cookie.DeviceUIDFromContext(ctx); uid != ksuid.Nil {
+		subject.DeviceUID = uid.String()
+	}
+	if session := session.FromContext(ctx); session != nil {
+		subject.UserUID = session.UserID
+	}
+	subject.Principal = PrincipalFromContext(ctx)
+	return subject
+}
+
+// Reasons for the result of an evaluation.
+const (
+	ReasonNotFound   = "not found"
+	ReasonOverride   = "override"
+	ReasonDisabled   = "disabled"
+	ReasonDenied     = "denied"
+	ReasonAllowed    = "allowed"
+	ReasonPercentage = "percentage"
+)
+
+// bucket returns the bucket (0-9999) of the subject key for the flag.
+func bucket(flagName, key string) uint64 {
+	sum := sha256.Sum256([]byte(flagName + "\x00" + key))
+	return binary.BigEndian.Uint64(sum[:8]) % 10000
+}
+
+// evaluate evaluates the flag for the subject.
+func (f *Flag) evaluate(subject Subject) (bool, string) {
+	if !f.Enabled {
+		return false, ReasonDisabled
+	}
+	if subject.in(f.Deny) {
+		return false, ReasonDenied
+	}
+	if subject.in(f.Allow) {
+		return true, ReasonAllowed
+	}
+	if f.Percentage >= 100 {
+		return true, ReasonPercentage
+	}
+	key := subject.key()
+	if key == "" || f.Percentage <= 0 {
+		return false, ReasonPercentage
+	}
+	return bucket(f.Name, key) < uint64(f.Percentage*100), ReasonPercentage
+}
+
+// Flags is a set of feature flags with runtime overrides.
+type Flags struct {
+	mu        sync.RWMutex
+	flags     map[string]*Flag
+	overrides map[string]Override
+}
+
+// NewFlags returns a new set of feature flags.
+func NewFlags(flags ...Flag) *Flags {
+	f := &Flags{overrides: make(map[string]Override)}
+	f.Set(flags...)
+	return f
+}
+
+// Set replaces the flags. Overrides are kept.
+func (f *Flags) Set(flags ...Flag) {
+	m := make(map[string]*Flag, len(flags))
+	for i := range flags {
+		flag := flags[i]
+		m[flag.Name] = &flag
+	}
+	f.mu.Lock()
+	f.flags = m
+	f.mu.Unlock()
+}
+
+// List returns the flags and their overrides, sorted by name.
+func (f *Flags) List() ([]Flag, []Override) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	flags := make([]Flag, 0, len(f.flags))
+	for _, flag := range f.flags {
+		flags = append(flags, *flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	overrides := make([]Override, len(flags))
+	for i, flag := range flags {
+		overrides[i] = f.overrides[flag.Name]
+	}
+	return flags, overrides
+}
+
+// SetOverride sets the override of the flag with the given name.
+func (f *Flags) SetOverride(name string, override Override) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if override == OverrideNone {
+		delete(f.overrides, name)
+		return
+	}
+	f.overrides[name] = override
+}
+
+// Evaluate evaluates the flag with the given name for the subject,
+// and returns the result and the reason for the result.
+// Flags that do not exist are disabled.
+func (f *Flags) Evaluate(name string, subject Subject) (bool, string) {
+	f.mu.RLock()
+	flag, ok := f.flags[name]
+	override := f.overrides[name]
+	f.mu.RUnlock()
+	switch override {
+	case OverrideOn:
+		return true, ReasonOverride
+	case OverrideOff:
+		return false, ReasonOverride
+	}
+	if !ok {
+		return false, ReasonNotFound
+	}
+	return flag.evaluate(subject)
+}
+
+// Enabled returns whether the flag with the given name is enabled for the subject in the context.
+func (f *Flags) Enabled(ctx context.Context, name string) bool {
+	enabled, _ := f.Evaluate(name, SubjectFromContext(ctx))
+	return enabled
+}
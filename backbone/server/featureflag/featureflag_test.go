@@ -0,0 +1,107 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	featureflag "htdvisser.dev/exp/backbone/api/featureflag/v1alpha1"
+	"htdvisser.dev/exp/backbone/backbonetest"
+)
+
+func TestEvaluate(t *testing.T) {
+	flags := NewFlags(
+		Flag{Name: "off", Enabled: false, Percentage: 100},
+		Flag{Name: "rollout", Enabled: true, Percentage: 25, Allow: []string{"alice"}, Deny: []string{"bob"}},
+	)
+
+	if enabled, reason := flags.Evaluate("off", Subject{Principal: "alice"}); enabled || reason != ReasonDisabled {
+		t.Errorf("disabled flag evaluated to %v (%s)", enabled, reason)
+	}
+	if enabled, reason := flags.Evaluate("unknown", Subject{Principal: "alice"}); enabled || reason != ReasonNotFound {
+		t.Errorf("unknown flag evaluated to %v (%s)", enabled, reason)
+	}
+	if enabled, reason := flags.Evaluate("rollout", Subject{Principal: "alice"}); !enabled || reason != ReasonAllowed {
+		t.Errorf("allowed subject evaluated to %v (%s)", enabled, reason)
+	}
+	if enabled, reason := flags.Evaluate("rollout", Subject{DeviceUID: "device", UserUID: "bob"}); enabled || reason != ReasonDenied {
+		t.Errorf("denied subject evaluated to %v (%s)", enabled, reason)
+	}
+
+	var count int
+	for i := 0; i < 10000; i++ {
+		subject := Subject{DeviceUID: fmt.Sprintf("device-%d", i)}
+		enabled, _ := flags.Evaluate("rollout", subject)
+		if again, _ := flags.Evaluate("rollout", subject); again != enabled {
+			t.Fatalf("evaluation for %v is not stable", subject)
+		}
+		if enabled {
+			count++
+		}
+	}
+	if count < 2300 || count > 2700 {
+		t.Errorf("rollout of 25%% enabled the flag for %d of 10000 subjects", count)
+	}
+
+	flags.SetOverride("off", OverrideOn)
+	if enabled, reason := flags.Evaluate("off", Subject{}); !enabled || reason != ReasonOverride {
+		t.Errorf("overridden flag evaluated to %v (%s)", enabled, reason)
+	}
+	if !flags.Enabled(NewContextWithPrincipal(context.Background(), "alice"), "rollout") {
+		t.Error("flag not enabled for principal in context")
+	}
+}
+
+func TestService(t *testing.T) {
+	flags := NewFlags(Flag{Name: "new-ui", Enabled: true, Percentage: 0})
+
+	h := backbonetest.New(t)
+	if err := Register(context.Background(), h.Server, flags); err != nil {
+		t.Fatal(err)
+	}
+	h.Start()
+
+	client := featureflag.NewFeatureFlagServiceClient(h.InternalGRPCConn())
+	flag, err := client.SetOverride(context.Background(), &featureflag.SetOverrideRequest{
+		Name:     "new-ui",
+		Override: featureflag.Flag_ON,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flag.GetOverride() != featureflag.Flag_ON || !flags.Enabled(context.Background(), "new-ui") {
+		t.Errorf("override was not set: %v", flag)
+	}
+
+	res, err := h.InternalHTTP().Client().Post(
+		h.InternalHTTP().URL+"/featureflag/v1alpha1/flags/new-ui/evaluate",
+		"application/json", strings.NewReader(`{"subject":{"principal":"alice"}}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %s", res.Status)
+	}
+	var evaluation struct {
+		Enabled bool   `json:"enabled"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&evaluation); err != nil {
+		t.Fatal(err)
+	}
+	if !evaluation.Enabled || evaluation.Reason != ReasonOverride {
+		t.Errorf("unexpected evaluation: %+v", evaluation)
+	}
+}
+
+func TestWatchInvalidInterval(t *testing.T) {
+	source := SourceFunc(func(context.Context) ([]Flag, error) { return nil, nil })
+	if err := NewFlags().Watch(context.Background(), source, 0); err == nil {
+		t.Error("expected error for zero watch interval")
+	}
+}
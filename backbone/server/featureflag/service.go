@@ -0,0 +1,91 @@
+package featureflag
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	featureflag "htdvisser.dev/exp/backbone/api/featureflag/v1alpha1"
+	"htdvisser.dev/exp/backbone/server"
+)
+
+// Service is the feature flag service that is used to inspect and override flags.
+type Service struct {
+	flags *Flags
+
+	featureflag.UnimplementedFeatureFlagServiceServer
+}
+
+// NewService returns a new feature flag service for the flags.
+func NewService(flags *Flags) *Service {
+	return &Service{flags: flags}
+}
+
+// Register registers the feature flag service to the internal gRPC server,
+// and its gateway routes to the internal HTTP server.
+func (svc *Service) Register(ctx context.Context, s *server.Server) error {
+	featureflag.RegisterFeatureFlagServiceServer(s.InternalGRPC, svc)
+	if err := featureflag.RegisterFeatureFlagServiceHandlerClient(ctx, s.InternalGRPC.Gateway, featureflag.NewFeatureFlagServiceClient(s.InternalGRPC.InProcessConn())); err != nil {
+		return err
+	}
+	s.InternalHTTP.ServeMux.Handle("/featureflag/", s.InternalGRPC.Gateway)
+	return nil
+}
+
+// Register registers the feature flag service for the flags to the server.
+func Register(ctx context.Context, s *server.Server, flags *Flags) error {
+	return NewService(flags).Register(ctx, s)
+}
+
+func toProto(flag Flag, override Override) *featureflag.Flag {
+	return &featureflag.Flag{
+		Name:        flag.Name,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Percentage:  flag.Percentage,
+		Allow:       flag.Allow,
+		Deny:        flag.Deny,
+		Override:    featureflag.Flag_Override(override),
+	}
+}
+
+// ListFlags implements the FeatureFlagService interface.
+func (svc *Service) ListFlags(context.Context, *featureflag.ListFlagsRequest) (*featureflag.ListFlagsResponse, error) {
+	var res featureflag.ListFlagsResponse
+	flags, overrides := svc.flags.List()
+	for i, flag := range flags {
+		res.Flags = append(res.Flags, toProto(flag, overrides[i]))
+	}
+	return &res, nil
+}
+
+// SetOverride implements the FeatureFlagService interface.
+func (svc *Service) SetOverride(_ context.Context, req *featureflag.SetOverrideRequest) (*featureflag.Flag, error) {
+	switch req.GetOverride() {
+	case featureflag.Flag_NONE, featureflag.Flag_ON, featureflag.Flag_OFF:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid override %s", req.GetOverride())
+	}
+	svc.flags.SetOverride(req.GetName(), Override(req.GetOverride()))
+	flags, overrides := svc.flags.List()
+	for i, flag := range flags {
+		if flag.Name == req.GetName() {
+			return toProto(flag, overrides[i]), nil
+		}
+	}
+	// Overrides can be set for flags that are not (yet) loaded.
+	return toProto(Flag{Name: req.GetName()}, Override(req.GetOverride())), nil
+}
+
+// Evaluate implements the FeatureFlagService interface.
+func (svc *Service) Evaluate(_ context.Context, req *featureflag.EvaluateRequest) (*featureflag.EvaluateResponse, error) {
+	enabled, reason := svc.flags.Evaluate(req.GetName(), Subject{
+		DeviceUID: req.GetSubject().GetDeviceUid(),
+		UserUID:   req.GetSubject().GetUserUid(),
+		Principal: req.GetSubject().GetPrincipal(),
+	})
+	return &featureflag.EvaluateResponse{
+		Enabled: enabled,
+		Reason:  reason,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is a source of feature flags.
+type Source interface {
+	Load(ctx context.Context) ([]Flag, error)
+}
+
+// SourceFunc is a function that implements Source.
+type SourceFunc func(ctx context.Context) ([]Flag, error)
+
+// Load implements Source.
+func (f SourceFunc) Load(ctx context.Context) ([]Flag, error) {
+	return f(ctx)
+}
+
+// FileSource returns a Source that loads flags from the YAML or JSON file with the given name.
+// The file contains a list of flags.
+func FileSource(name string) Source {
+	return SourceFunc(func(context.Context) ([]Flag, error) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var flags []Flag
+		if err := yaml.Unmarshal(data, &flags); err != nil {
+			return nil, fmt.Errorf("featureflag: could not decode %q: %w", name, err)
+		}
+		return flags, nil
+	})
+}
+
+// RedisSource returns a Source that loads flags from the Redis hash with the given key.
+// The fields of the hash are the names of the flags, and the values are JSON encoded flags.
+func RedisSource(client redis.UniversalClient, key string) Source {
+	return SourceFunc(func(ctx context.Context) ([]Flag, error) {
+		values, err := client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		flags := make([]Flag, 0, len(values))
+		for name, value := range values {
+			var flag Flag
+			if err := json.Unmarshal([]byte(value), &flag); err != nil {
+				return nil, fmt.Errorf("featureflag: could not decode flag %q: %w", name, err)
+			}
+			flag.Name = name
+			flags = append(flags, flag)
+		}
+		return flags, nil
+	})
+}
+
+// Load loads the flags from the source.
+func (f *Flags) Load(ctx context.Context, source Source) error {
+	flags, err := source.Load(ctx)
+	if err != nil {
+		return err
+	}
+	f.Set(flags...)
+	return nil
+}
+
+// Watch reloads the flags from the source at the given interval until ctx is done.
+// Errors while reloading are logged, and the previous flags are kept.
+// The interval must be positive.
+func (f *Flags) Watch(ctx context.Context, source Source, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("featureflag: invalid watch interval %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := f.Load(ctx, source); err != nil {
+				log.Printf("Failed to reload feature flags: %v", err)
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
@@ -13,9 +14,10 @@ import (
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/stats"
+	serverhttp "htdvisser.dev/exp/backbone/server/http"
 )
 
-// Server wraps the gRPC server, gRPC-gateway and a loopback connection.
+// Server wraps the gRPC server, gRPC-gateway and loopback and in-process connections.
 type Server struct {
 	Server  *grpc.Server
 	Web     *grpcweb.WrappedGrpcServer
@@ -26,6 +28,11 @@ type Server struct {
 	loopbackServing  bool
 	loopbackConn     *grpc.ClientConn
 
+	servicesMu sync.RWMutex
+	services   map[string]*registeredService
+
+	transcoding bool
+
 	contextExtenders []func(context.Context) context.Context
 
 	unaryInterceptors  []grpc.UnaryServerInterceptor
@@ -51,6 +58,10 @@ func NewServer(opts ...Option) *Server {
 
 		loopbackListener: newInProcessListener(context.Background()),
 
+		services: make(map[string]*registeredService),
+
+		transcoding: options.transcoding,
+
 		contextExtenders: options.contextExtenders,
 
 		unaryInterceptors:  options.gRPCUnaryInterceptors,
@@ -63,9 +74,7 @@ func NewServer(opts ...Option) *Server {
 		grpc.StreamInterceptor(s.interceptStream),
 		grpc.StatsHandler(&statsHandler{s}),
 	)
-	grpcWebOptions := append(
-		options.grpcWebOptions,
-	)
+	grpcWebOptions := options.grpcWebOptions
 	runtimeServeMuxOptions := append(
 		options.runtimeServeMuxOptions,
 		runtime.WithIncomingHeaderMatcher(options.runtimeIncomingHeaders.match),
@@ -74,7 +83,7 @@ func NewServer(opts ...Option) *Server {
 	s.Server = grpc.NewServer(gRPCServerOptions...)
 	s.Web = grpcweb.WrapServer(s.Server, grpcWebOptions...)
 	s.Gateway = runtime.NewServeMux(runtimeServeMuxOptions...)
-	healthpb.RegisterHealthServer(s.Server, s.Health)
+	healthpb.RegisterHealthServer(s, s.Health)
 	return s
 }
 
@@ -104,7 +113,10 @@ func (s *Server) Serve(lis net.Listener) error {
 	return s.Server.Serve(lis)
 }
 
+// ServeHTTP serves gRPC and gRPC-Web requests. The body size limit of the HTTP
+// server is removed, because the gRPC server limits the size of messages.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serverhttp.RemoveBodyLimit(r)
 	contentType := r.Header.Get("Content-Type")
 	switch {
 	case s.Web.IsGrpcWebRequest(r) || s.Web.IsAcceptableGrpcCorsRequest(r):
@@ -0,0 +1,502 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type registeredService struct {
+	impl    interface{}
+	methods map[string]*grpc.MethodDesc
+	streams map[string]*grpc.StreamDesc
+}
+
+// RegisterService registers a service and its implementation to the gRPC server.
+// It implements grpc.ServiceRegistrar, so that generated RegisterXServer funcs
+// can be used with the Server. Services that are registered this way can be
+// called directly by the in-process connection (see InProcessConn).
+func (s *Server) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.Server.RegisterService(desc, impl)
+	service := &registeredService{
+		impl:    impl,
+		methods: make(map[string]*grpc.MethodDesc, len(desc.Methods)),
+		streams: make(map[string]*grpc.StreamDesc, len(desc.Streams)),
+	}
+	for i := range desc.Methods {
+		service.methods[desc.Methods[i].MethodName] = &desc.Methods[i]
+	}
+	for i := range desc.Streams {
+		service.streams[desc.Streams[i].StreamName] = &desc.Streams[i]
+	}
+	s.servicesMu.Lock()
+	s.services[desc.ServiceName] = service
+	s.servicesMu.Unlock()
+	if s.transcoding {
+		if service, ok := findService(desc.ServiceName); ok {
+			if err := s.Transcode(service); err != nil {
+				log.Printf("Failed to transcode %s: %v", desc.ServiceName, err)
+			}
+		}
+	}
+}
+
+func splitMethod(fullMethod string) (service, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
+
+func (s *Server) lookupService(fullMethod string) (*registeredService, string) {
+	serviceName, methodName := splitMethod(fullMethod)
+	s.servicesMu.RLock()
+	defer s.servicesMu.RUnlock()
+	return s.services[serviceName], methodName
+}
+
+// InProcessConn returns an in-process gRPC connection to the server.
+//
+// Calls to services that were registered with RegisterService invoke the service
+// handlers directly, without marshaling the messages or framing them over HTTP/2.
+// Messages are cloned, so that the caller and the handler never share messages.
+// The interceptors, context extenders and stats handlers of the server are still used.
+// Calls to other services go through the LoopbackConn.
+//
+// Use it with the generated RegisterXHandlerClient funcs of the gRPC-gateway:
+//
+//	pb.RegisterXHandlerClient(ctx, s.Gateway, pb.NewXClient(s.InProcessConn()))
+func (s *Server) InProcessConn() grpc.ClientConnInterface {
+	return inProcessConn{s}
+}
+
+type inProcessConn struct {
+	*Server
+}
+
+var inProcessPeer = &peer.Peer{
+	Addr:     inProcessAddr(inProcess),
+	AuthInfo: inProcessAuthInfo{},
+}
+
+// serverContext returns the context for the handler. The outgoing metadata of the
+// client becomes the incoming metadata of the server.
+func serverContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	ctx = metadata.NewOutgoingContext(ctx, nil)
+	ctx = metadata.NewIncomingContext(ctx, md.Copy())
+	return peer.NewContext(ctx, inProcessPeer)
+}
+
+// cloneInto replaces the contents of dst with a copy of src.
+func cloneInto(dst, src interface{}) error {
+	dstMsg, ok := dst.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "in-process: %T is not a proto message", dst)
+	}
+	srcMsg, ok := src.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "in-process: %T is not a proto message", src)
+	}
+	if dstMsg.ProtoReflect().Descriptor() != srcMsg.ProtoReflect().Descriptor() {
+		// Dynamic messages (see Transcode) have a different descriptor than
+		// the generated messages of the service.
+		b, err := proto.Marshal(srcMsg)
+		if err != nil {
+			return status.Errorf(codes.Internal, "in-process: %v", err)
+		}
+		if err := proto.Unmarshal(b, dstMsg); err != nil {
+			return status.Errorf(codes.Internal, "in-process: %v", err)
+		}
+		return nil
+	}
+	proto.Reset(dstMsg)
+	proto.Merge(dstMsg, srcMsg)
+	return nil
+}
+
+func clone(msg interface{}) (interface{}, error) {
+	protoMsg, ok := msg.(proto.Message)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "in-process: %T is not a proto message", msg)
+	}
+	return proto.Clone(protoMsg), nil
+}
+
+// toStatusError converts errors returned by handlers like the gRPC server does.
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// callMetadata receives the header and trailer for grpc.Header and grpc.Trailer call options.
+type callMetadata struct {
+	header  []*metadata.MD
+	trailer []*metadata.MD
+}
+
+func newCallMetadata(opts []grpc.CallOption) *callMetadata {
+	var m callMetadata
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case grpc.HeaderCallOption:
+			m.header = append(m.header, opt.HeaderAddr)
+		case grpc.TrailerCallOption:
+			m.trailer = append(m.trailer, opt.TrailerAddr)
+		}
+	}
+	return &m
+}
+
+func (m *callMetadata) setHeader(md metadata.MD) {
+	for _, addr := range m.header {
+		*addr = md
+	}
+}
+
+func (m *callMetadata) setTrailer(md metadata.MD) {
+	for _, addr := range m.trailer {
+		*addr = md
+	}
+}
+
+// transportStream implements grpc.ServerTransportStream for unary calls.
+type transportStream struct {
+	method string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	headerSent bool
+	trailer    metadata.MD
+}
+
+func (t *transportStream) Method() string { return t.method }
+
+func (t *transportStream) SetHeader(md metadata.MD) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.headerSent {
+		return status.Error(codes.Internal, "in-process: header already sent")
+	}
+	t.header = metadata.Join(t.header, md)
+	return nil
+}
+
+func (t *transportStream) SendHeader(md metadata.MD) error {
+	if err := t.SetHeader(md); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.headerSent = true
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *transportStream) SetTrailer(md metadata.MD) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.trailer = metadata.Join(t.trailer, md)
+	return nil
+}
+
+func (c inProcessConn) beginRPC(ctx context.Context, method string, clientStream, serverStream bool) (context.Context, func(error)) {
+	if len(c.statsHandlers) == 0 {
+		return ctx, func(error) {}
+	}
+	handler := &statsHandler{c.Server}
+	ctx = handler.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method})
+	begin := time.Now()
+	handler.HandleRPC(ctx, &stats.Begin{
+		BeginTime:      begin,
+		IsClientStream: clientStream,
+		IsServerStream: serverStream,
+	})
+	return ctx, func(err error) {
+		handler.HandleRPC(ctx, &stats.End{
+			BeginTime: begin,
+			EndTime:   time.Now(),
+			Error:     err,
+		})
+	}
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c inProcessConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	service, methodName := c.lookupService(method)
+	if service == nil || service.methods[methodName] == nil {
+		return c.LoopbackConn().Invoke(ctx, method, args, reply, opts...)
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	callMetadata := newCallMetadata(opts)
+	stream := &transportStream{method: method}
+	ctx = grpc.NewContextWithServerTransportStream(serverContext(ctx), stream)
+	ctx, end := c.beginRPC(ctx, method, false, false)
+	dec := func(v interface{}) error {
+		return cloneInto(v, args)
+	}
+	res, err := service.methods[methodName].Handler(service.impl, ctx, dec, c.interceptUnary)
+	err = toStatusError(err)
+	end(err)
+	stream.mu.Lock()
+	callMetadata.setHeader(stream.header)
+	callMetadata.setTrailer(stream.trailer)
+	stream.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return cloneInto(reply, res)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c inProcessConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	service, methodName := c.lookupService(method)
+	if service == nil || service.streams[methodName] == nil {
+		return c.LoopbackConn().NewStream(ctx, desc, method, opts...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	streamDesc := service.streams[methodName]
+	serverCtx, cancel := context.WithCancel(serverContext(ctx))
+	serverCtx, end := c.beginRPC(serverCtx, method, streamDesc.ClientStreams, streamDesc.ServerStreams)
+	s := &inProcessStream{
+		clientCtx:    ctx,
+		serverCtx:    serverCtx,
+		cancel:       cancel,
+		method:       method,
+		callMetadata: newCallMetadata(opts),
+		toServer:     make(chan interface{}),
+		toClient:     make(chan interface{}),
+		closeSend:    make(chan struct{}),
+		headerSent:   make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	s.serverCtx = grpc.NewContextWithServerTransportStream(s.serverCtx, (*inProcessTransportStream)(s))
+	go func() {
+		err := toStatusError(c.interceptStream(service.impl, (*inProcessServerStream)(s), &grpc.StreamServerInfo{
+			FullMethod:     method,
+			IsClientStream: streamDesc.ClientStreams,
+			IsServerStream: streamDesc.ServerStreams,
+		}, streamDesc.Handler))
+		end(err)
+		s.finish(err)
+	}()
+	return (*inProcessClientStream)(s), nil
+}
+
+// inProcessStream is the state of an in-process stream.
+// It is used by both the client (inProcessClientStream) and the server (inProcessServerStream).
+type inProcessStream struct {
+	clientCtx    context.Context
+	serverCtx    context.Context
+	cancel       context.CancelFunc
+	method       string
+	callMetadata *callMetadata
+
+	toServer      chan interface{}
+	toClient      chan interface{}
+	closeSend     chan struct{}
+	closeSendOnce sync.Once
+
+	mu             sync.Mutex
+	header         metadata.MD
+	headerSent     chan struct{}
+	headerSentOnce sync.Once
+	trailer        metadata.MD
+	err            error
+	done           chan struct{}
+}
+
+func (s *inProcessStream) sendHeader() {
+	s.headerSentOnce.Do(func() {
+		s.mu.Lock()
+		s.callMetadata.setHeader(s.header)
+		s.mu.Unlock()
+		close(s.headerSent)
+	})
+}
+
+func (s *inProcessStream) setHeader(md metadata.MD) error {
+	select {
+	case <-s.headerSent:
+		return status.Error(codes.Internal, "in-process: header already sent")
+	default:
+	}
+	s.mu.Lock()
+	s.header = metadata.Join(s.header, md)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *inProcessStream) sendHeaderWith(md metadata.MD) error {
+	if err := s.setHeader(md); err != nil {
+		return err
+	}
+	s.sendHeader()
+	return nil
+}
+
+func (s *inProcessStream) setTrailer(md metadata.MD) {
+	s.mu.Lock()
+	s.trailer = metadata.Join(s.trailer, md)
+	s.mu.Unlock()
+}
+
+func (s *inProcessStream) finish(err error) {
+	s.sendHeader()
+	s.mu.Lock()
+	s.err = err
+	s.callMetadata.setTrailer(s.trailer)
+	s.mu.Unlock()
+	close(s.done)
+	s.cancel()
+}
+
+type inProcessClientStream inProcessStream
+
+func (s *inProcessClientStream) Header() (metadata.MD, error) {
+	select {
+	case <-s.headerSent:
+	case <-s.clientCtx.Done():
+		return nil, status.FromContextError(s.clientCtx.Err()).Err()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.header.Copy(), nil
+}
+
+func (s *inProcessClientStream) Trailer() metadata.MD {
+	select {
+	case <-s.done:
+	default:
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trailer.Copy()
+}
+
+func (s *inProcessClientStream) CloseSend() error {
+	s.closeSendOnce.Do(func() { close(s.closeSend) })
+	return nil
+}
+
+func (s *inProcessClientStream) Context() context.Context { return s.clientCtx }
+
+func (s *inProcessClientStream) SendMsg(m interface{}) error {
+	msg, err := clone(m)
+	if err != nil {
+		return err
+	}
+	select {
+	case s.toServer <- msg:
+		return nil
+	case <-s.done:
+		// Like gRPC, the status is returned by RecvMsg.
+		return io.EOF
+	case <-s.clientCtx.Done():
+		return status.FromContextError(s.clientCtx.Err()).Err()
+	}
+}
+
+func (s *inProcessClientStream) RecvMsg(m interface{}) error {
+	select {
+	case msg := <-s.toClient:
+		return cloneInto(m, msg)
+	case <-s.done:
+		s.mu.Lock()
+		err := s.err
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		return io.EOF
+	case <-s.clientCtx.Done():
+		return status.FromContextError(s.clientCtx.Err()).Err()
+	}
+}
+
+type inProcessServerStream inProcessStream
+
+func (s *inProcessServerStream) Method() string { return s.method }
+
+func (s *inProcessServerStream) SetHeader(md metadata.MD) error {
+	return (*inProcessStream)(s).setHeader(md)
+}
+
+func (s *inProcessServerStream) SendHeader(md metadata.MD) error {
+	return (*inProcessStream)(s).sendHeaderWith(md)
+}
+
+func (s *inProcessServerStream) SetTrailer(md metadata.MD) {
+	(*inProcessStream)(s).setTrailer(md)
+}
+
+func (s *inProcessServerStream) Context() context.Context { return s.serverCtx }
+
+func (s *inProcessServerStream) SendMsg(m interface{}) error {
+	(*inProcessStream)(s).sendHeader()
+	msg, err := clone(m)
+	if err != nil {
+		return err
+	}
+	select {
+	case s.toClient <- msg:
+		return nil
+	case <-s.serverCtx.Done():
+		return status.FromContextError(s.serverCtx.Err()).Err()
+	}
+}
+
+func (s *inProcessServerStream) RecvMsg(m interface{}) error {
+	select {
+	case msg := <-s.toServer:
+		return cloneInto(m, msg)
+	case <-s.closeSend:
+		return io.EOF
+	case <-s.serverCtx.Done():
+		return status.FromContextError(s.serverCtx.Err()).Err()
+	}
+}
+
+// inProcessTransportStream implements grpc.ServerTransportStream for grpc.SetHeader,
+// grpc.SendHeader and grpc.SetTrailer in stream handlers.
+type inProcessTransportStream inProcessStream
+
+func (s *inProcessTransportStream) Method() string { return s.method }
+
+func (s *inProcessTransportStream) SetHeader(md metadata.MD) error {
+	return (*inProcessStream)(s).setHeader(md)
+}
+
+func (s *inProcessTransportStream) SendHeader(md metadata.MD) error {
+	return (*inProcessStream)(s).sendHeaderWith(md)
+}
+
+func (s *inProcessTransportStream) SetTrailer(md metadata.MD) error {
+	(*inProcessStream)(s).setTrailer(md)
+	return nil
+}
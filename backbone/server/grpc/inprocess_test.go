@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestInProcessConn(t *testing.T) {
+	var unaryCalls, streamCalls int
+	s := NewServer(
+		WithUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			unaryCalls++
+			if md, _ := metadata.FromIncomingContext(ctx); len(md.Get("x-test")) != 1 {
+				t.Errorf("incoming metadata %v does not contain x-test", md)
+			}
+			if p, ok := peer.FromContext(ctx); !ok || p.Addr.Network() != inProcess {
+				t.Errorf("unexpected peer %v", p)
+			}
+			grpc.SetHeader(ctx, metadata.Pairs("x-header", "value"))
+			return handler(ctx, req)
+		}),
+		WithStreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			streamCalls++
+			return handler(srv, ss)
+		}),
+	)
+	client := healthpb.NewHealthClient(s.InProcessConn())
+
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), "x-test", "value"))
+	defer cancel()
+
+	var header metadata.MD
+	res, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status is %v, want SERVING", res.GetStatus())
+	}
+	if got := header.Get("x-header"); len(got) != 1 || got[0] != "value" {
+		t.Errorf("header is %v, want x-header", header)
+	}
+	if unaryCalls != 1 {
+		t.Errorf("unary interceptor called %d times, want 1", unaryCalls)
+	}
+
+	s.Health.SetServingStatus("test", healthpb.HealthCheckResponse_SERVING)
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status is %v, want SERVING", res.GetStatus())
+	}
+	s.Health.SetServingStatus("test", healthpb.HealthCheckResponse_NOT_SERVING)
+	res, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status is %v, want NOT_SERVING", res.GetStatus())
+	}
+	if streamCalls != 1 {
+		t.Errorf("stream interceptor called %d times, want 1", streamCalls)
+	}
+}
@@ -19,6 +19,7 @@ type options struct {
 	runtimeServeMuxOptions []runtime.ServeMuxOption
 	runtimeIncomingHeaders runtimeHeaders
 	runtimeOutgoingHeaders runtimeHeaders
+	transcoding            bool
 }
 
 func (o *options) apply(opts ...Option) {
@@ -0,0 +1,82 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotatingFile is a file that is rotated when it reaches its maximum size.
+type rotatingFile struct {
+	name     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(name string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	f := &rotatingFile{name: name, maxSize: maxSize, maxFiles: maxFiles}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+func rotatedName(name string, i int) string {
+	return fmt.Sprintf("%s.%d", name, i)
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	os.Remove(rotatedName(f.name, f.maxFiles))
+	for i := f.maxFiles - 1; i > 0; i-- {
+		os.Rename(rotatedName(f.name, i), rotatedName(f.name, i+1))
+	}
+	if f.maxFiles > 0 {
+		if err := os.Rename(f.name, rotatedName(f.name, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(f.name); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// Write writes p to the file. It does not split p over multiple files.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,155 @@
+// Package recording records sampled gRPC calls to files, so that they can be
+// replayed against a server with the grpc-replay command.
+//
+// # Format
+//
+// A recording is a file with one JSON object per line (JSON Lines). Each object
+// is a Record of a single call:
+//
+//	{
+//	  "time": "2023-12-06T18:53:58.123456Z",       // Start of the call (RFC 3339).
+//	  "duration": "1.234ms",                       // Duration of the call.
+//	  "method": "/pkg.Service/Method",             // Full gRPC method name.
+//	  "client_streams": false,                     // Whether the client streams requests.
+//	  "server_streams": false,                     // Whether the server streams responses.
+//	  "metadata": {"user-agent": ["..."]},         // Incoming request metadata.
+//	  "requests": [{"field": "value"}],            // Request messages in protobuf JSON.
+//	  "responses": [{"field": "value"}],           // Response messages in protobuf JSON.
+//	  "header": {"key": ["value"]},                // Header sent by the server.
+//	  "trailer": {"key": ["value"]},               // Trailer sent by the server.
+//	  "code": "OK",                                // gRPC status code.
+//	  "message": "",                               // gRPC status message.
+//	  "redacted": ["user.password"],               // Field paths that were removed.
+//	  "truncated": false                           // Whether stream messages were left out.
+//	}
+//
+// Messages are marshaled with the field names of the proto files. Values of binary
+// metadata (with keys ending in "-bin") are base64 encoded. Redacted metadata values
+// are replaced with "REDACTED", redacted message fields are removed. Only the first
+// 100 messages in each direction of a stream are recorded. Calls to the server
+// reflection service are not recorded.
+//
+// When a file reaches its maximum size, it is renamed to "<name>.1" (and existing
+// rotated files to "<name>.2" and so on) and a new file is started.
+package recording
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Record is the record of a single gRPC call.
+type Record struct {
+	Time          time.Time         `json:"time"`
+	Duration      Duration          `json:"duration"`
+	Method        string            `json:"method"`
+	ClientStreams bool              `json:"client_streams,omitempty"`
+	ServerStreams bool              `json:"server_streams,omitempty"`
+	Metadata      Metadata          `json:"metadata,omitempty"`
+	Requests      []json.RawMessage `json:"requests"`
+	Responses     []json.RawMessage `json:"responses"`
+	Header        Metadata          `json:"header,omitempty"`
+	Trailer       Metadata          `json:"trailer,omitempty"`
+	Code          string            `json:"code"`
+	Message       string            `json:"message,omitempty"`
+	Redacted      []string          `json:"redacted,omitempty"`
+	Truncated     bool              `json:"truncated,omitempty"`
+}
+
+// Duration is a time.Duration that is marshaled as a string such as "1.5ms".
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// Metadata is gRPC metadata in which binary values are base64 encoded.
+type Metadata map[string][]string
+
+// RedactedValue replaces redacted metadata values.
+const RedactedValue = "REDACTED"
+
+func isBinaryKey(key string) bool {
+	return strings.HasSuffix(key, "-bin")
+}
+
+func newMetadata(md metadata.MD, redact map[string]bool) Metadata {
+	if len(md) == 0 {
+		return nil
+	}
+	out := make(Metadata, len(md))
+	for key, values := range md {
+		outValues := make([]string, len(values))
+		for i, value := range values {
+			switch {
+			case redact[key]:
+				outValues[i] = RedactedValue
+			case isBinaryKey(key):
+				outValues[i] = base64.StdEncoding.EncodeToString([]byte(value))
+			default:
+				outValues[i] = value
+			}
+		}
+		out[key] = outValues
+	}
+	return out
+}
+
+// MD returns the metadata as gRPC metadata, decoding binary values.
+func (m Metadata) MD() (metadata.MD, error) {
+	md := make(metadata.MD, len(m))
+	for key, values := range m {
+		for _, value := range values {
+			if isBinaryKey(key) && value != RedactedValue {
+				decoded, err := base64.StdEncoding.DecodeString(value)
+				if err != nil {
+					return nil, fmt.Errorf("recording: invalid binary metadata %q: %w", key, err)
+				}
+				value = string(decoded)
+			}
+			md.Append(key, value)
+		}
+	}
+	return md, nil
+}
+
+// maxRecordSize is the maximum size of a line in a recording.
+const maxRecordSize = 64 << 20
+
+// ReadRecords reads the records from a recording and calls f for each record.
+// It stops when f returns an error, and returns that error.
+func ReadRecords(r io.Reader, f func(*Record) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("recording: invalid record on line %d: %w", line, err)
+		}
+		if err := f(&record); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
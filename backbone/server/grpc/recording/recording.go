@@ -0,0 +1,389 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"htdvisser.dev/exp/backbone/server"
+	"htdvisser.dev/exp/fieldpath"
+)
+
+// Config is the configuration for recording gRPC calls.
+type Config struct {
+	File        string
+	SampleRate  float64
+	MaxFileSize int64
+	MaxFiles    int
+	// RedactFields are the field paths (such as "user.password") that are
+	// removed from request and response messages of all methods. Paths that
+	// go through repeated fields or map fields are removed from all elements.
+	RedactFields []string
+	// RedactMetadata are the metadata keys of which the values are redacted.
+	// Keys are case insensitive.
+	RedactMetadata []string
+}
+
+// DefaultConfig returns the default config for recording gRPC calls.
+func DefaultConfig() *Config {
+	return &Config{
+		SampleRate:     0.01,
+		MaxFileSize:    100 << 20,
+		MaxFiles:       5,
+		RedactMetadata: []string{"authorization", "cookie"},
+	}
+}
+
+// Flags returns a flagset that can be added to the command line.
+func (c *Config) Flags(prefix string, defaults *Config) *pflag.FlagSet {
+	var flags pflag.FlagSet
+	if defaults == nil {
+		defaults = DefaultConfig()
+	}
+	flags.StringVar(&c.File, prefix+"recording.file", defaults.File, "File to record sampled gRPC calls to (disabled if empty)")
+	flags.Float64Var(&c.SampleRate, prefix+"recording.sample-rate", defaults.SampleRate, "Fraction of gRPC calls to record")
+	flags.Int64Var(&c.MaxFileSize, prefix+"recording.max-file-size", defaults.MaxFileSize, "Size in bytes at which the recording file is rotated")
+	flags.IntVar(&c.MaxFiles, prefix+"recording.max-files", defaults.MaxFiles, "Number of rotated recording files to keep")
+	flags.StringSliceVar(&c.RedactFields, prefix+"recording.redact-fields", defaults.RedactFields, "Field paths to remove from recorded messages")
+	flags.StringSliceVar(&c.RedactMetadata, prefix+"recording.redact-metadata", defaults.RedactMetadata, "Metadata keys to redact in recordings")
+	return &flags
+}
+
+// maxStreamMessages is the maximum number of messages that is recorded
+// for each direction of a stream.
+const maxStreamMessages = 100
+
+var marshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// Recorder records sampled gRPC calls.
+type Recorder struct {
+	sampleRate     float64
+	file           *rotatingFile
+	redactFields   fieldpath.List
+	redactMetadata map[string]bool
+}
+
+// NewRecorder returns a new Recorder that records to the file of the config.
+func NewRecorder(config Config) (*Recorder, error) {
+	if config.File == "" {
+		return nil, errors.New("recording: no file configured")
+	}
+	redactFields, err := fieldpath.ParseList(config.RedactFields...)
+	if err != nil {
+		return nil, err
+	}
+	redactMetadata := make(map[string]bool, len(config.RedactMetadata))
+	for _, key := range config.RedactMetadata {
+		redactMetadata[strings.ToLower(key)] = true
+	}
+	file, err := openRotatingFile(config.File, config.MaxFileSize, config.MaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		sampleRate:     config.SampleRate,
+		file:           file,
+		redactFields:   redactFields.Unique(false),
+		redactMetadata: redactMetadata,
+	}, nil
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
+
+// skipMethod returns whether calls to a method are never recorded.
+func skipMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.reflection.")
+}
+
+func (r *Recorder) sample() bool {
+	return r.sampleRate >= 1 || (r.sampleRate > 0 && rand.Float64() < r.sampleRate)
+}
+
+// marshal marshals msg to JSON without the redacted fields.
+func (r *Recorder) marshal(msg interface{}) (json.RawMessage, error) {
+	protoMsg, ok := msg.(proto.Message)
+	if !ok {
+		return nil, errors.New("recording: not a proto message")
+	}
+	if len(r.redactFields) > 0 {
+		protoMsg = proto.Clone(protoMsg)
+		for _, fp := range r.redactFields {
+			redact(protoMsg.ProtoReflect(), fp)
+		}
+	}
+	data, err := marshalOptions.Marshal(protoMsg)
+	if err != nil {
+		return nil, err
+	}
+	// The output of protojson is unstable, so it is compacted.
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// redact clears the field at fp in msg. If fp goes through a repeated field or
+// a map field, the field is cleared in each of the elements or values.
+func redact(msg protoreflect.Message, fp fieldpath.Path) {
+	if len(fp) == 0 {
+		return
+	}
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(fp[0]))
+	if fd == nil {
+		return // The field does not exist in this message.
+	}
+	if len(fp) == 1 {
+		msg.Clear(fd)
+		return
+	}
+	if !msg.Has(fd) {
+		return
+	}
+	switch {
+	case fd.IsList():
+		if fd.Message() == nil {
+			return
+		}
+		list := msg.Mutable(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			redact(list.Get(i).Message(), fp[1:])
+		}
+	case fd.IsMap():
+		if fd.MapValue().Message() == nil {
+			return
+		}
+		msg.Mutable(fd).Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+			redact(v.Message(), fp[1:])
+			return true
+		})
+	case fd.Message() != nil:
+		redact(msg.Mutable(fd).Message(), fp[1:])
+	}
+}
+
+// call is a call that is being recorded.
+type call struct {
+	recorder *Recorder
+	start    time.Time
+
+	mu     sync.Mutex
+	record Record
+}
+
+func (r *Recorder) newCall(ctx context.Context, method string, clientStreams, serverStreams bool) *call {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &call{
+		recorder: r,
+		start:    time.Now(),
+		record: Record{
+			Method:        method,
+			ClientStreams: clientStreams,
+			ServerStreams: serverStreams,
+			Metadata:      newMetadata(md, r.redactMetadata),
+			Requests:      []json.RawMessage{},
+			Responses:     []json.RawMessage{},
+			Redacted:      r.redactFields.Strings(),
+		},
+	}
+}
+
+func (c *call) add(messages *[]json.RawMessage, msg interface{}) {
+	data, err := c.recorder.marshal(msg)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(*messages) >= maxStreamMessages {
+		c.record.Truncated = true
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to record message of %s: %v", c.record.Method, err)
+		return
+	}
+	*messages = append(*messages, data)
+}
+
+func (c *call) addRequest(msg interface{}) { c.add(&c.record.Requests, msg) }
+
+func (c *call) addResponse(msg interface{}) { c.add(&c.record.Responses, msg) }
+
+func (c *call) setHeader(md metadata.MD) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.record.Header = mergeMetadata(c.record.Header, newMetadata(md, c.recorder.redactMetadata))
+}
+
+func (c *call) setTrailer(md metadata.MD) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.record.Trailer = mergeMetadata(c.record.Trailer, newMetadata(md, c.recorder.redactMetadata))
+}
+
+func mergeMetadata(dst, src Metadata) Metadata {
+	if dst == nil {
+		return src
+	}
+	for key, values := range src {
+		dst[key] = append(dst[key], values...)
+	}
+	return dst
+}
+
+func (c *call) finish(err error) {
+	c.mu.Lock()
+	record := c.record
+	c.mu.Unlock()
+	record.Time = c.start.UTC()
+	record.Duration = Duration(time.Since(c.start))
+	s := status.Convert(err)
+	record.Code, record.Message = s.Code().String(), s.Message()
+	data, err := json.Marshal(record)
+	if err == nil {
+		_, err = c.recorder.file.Write(append(data, '\n'))
+	}
+	if err != nil {
+		log.Printf("Failed to write recording of %s: %v", record.Method, err)
+	}
+}
+
+// transportStream records the header and trailer of unary calls.
+type transportStream struct {
+	grpc.ServerTransportStream
+	call *call
+}
+
+func (s *transportStream) SetHeader(md metadata.MD) error {
+	err := s.ServerTransportStream.SetHeader(md)
+	if err == nil {
+		s.call.setHeader(md)
+	}
+	return err
+}
+
+func (s *transportStream) SendHeader(md metadata.MD) error {
+	err := s.ServerTransportStream.SendHeader(md)
+	if err == nil {
+		s.call.setHeader(md)
+	}
+	return err
+}
+
+func (s *transportStream) SetTrailer(md metadata.MD) error {
+	err := s.ServerTransportStream.SetTrailer(md)
+	if err == nil {
+		s.call.setTrailer(md)
+	}
+	return err
+}
+
+// UnaryServerInterceptor returns a unary server interceptor that records sampled calls.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if skipMethod(info.FullMethod) || !r.sample() {
+			return handler(ctx, req)
+		}
+		call := r.newCall(ctx, info.FullMethod, false, false)
+		if stream := grpc.ServerTransportStreamFromContext(ctx); stream != nil {
+			ctx = grpc.NewContextWithServerTransportStream(ctx, &transportStream{ServerTransportStream: stream, call: call})
+		}
+		call.addRequest(req)
+		res, err := handler(ctx, req)
+		if err == nil {
+			call.addResponse(res)
+		}
+		call.finish(err)
+		return res, err
+	}
+}
+
+// serverStream records the messages, header and trailer of streaming calls.
+type serverStream struct {
+	grpc.ServerStream
+	call *call
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.call.addRequest(m)
+	}
+	return err
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.call.addResponse(m)
+	}
+	return err
+}
+
+func (s *serverStream) SetHeader(md metadata.MD) error {
+	err := s.ServerStream.SetHeader(md)
+	if err == nil {
+		s.call.setHeader(md)
+	}
+	return err
+}
+
+func (s *serverStream) SendHeader(md metadata.MD) error {
+	err := s.ServerStream.SendHeader(md)
+	if err == nil {
+		s.call.setHeader(md)
+	}
+	return err
+}
+
+func (s *serverStream) SetTrailer(md metadata.MD) {
+	s.ServerStream.SetTrailer(md)
+	s.call.setTrailer(md)
+}
+
+// StreamServerInterceptor returns a stream server interceptor that records sampled calls.
+func (r *Recorder) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if skipMethod(info.FullMethod) || !r.sample() {
+			return handler(srv, ss)
+		}
+		call := r.newCall(ss.Context(), info.FullMethod, info.IsClientStream, info.IsServerStream)
+		err := handler(srv, &serverStream{ServerStream: ss, call: call})
+		call.finish(err)
+		return err
+	}
+}
+
+// Register adds the interceptors of the recorder to the gRPC server.
+func (r *Recorder) Register(s *server.Server) {
+	s.GRPC.AddUnaryInterceptor(r.UnaryServerInterceptor())
+	s.GRPC.AddStreamInterceptor(r.StreamServerInterceptor())
+}
+
+// Register records sampled calls to the gRPC server if a file is configured.
+// It returns a nil Recorder if no file is configured.
+func Register(s *server.Server, config Config) (*Recorder, error) {
+	if config.File == "" {
+		return nil, nil
+	}
+	r, err := NewRecorder(config)
+	if err != nil {
+		return nil, err
+	}
+	r.Register(s)
+	return r, nil
+}
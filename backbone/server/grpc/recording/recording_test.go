@@ -0,0 +1,217 @@
+package recording
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/typepb"
+	bbgrpc "htdvisser.dev/exp/backbone/server/grpc"
+)
+
+func TestRecorder(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "recording.jsonl")
+
+	config := DefaultConfig()
+	config.File = name
+	config.SampleRate = 1
+	config.RedactFields = []string{"status"}
+	config.RedactMetadata = []string{"Authorization"}
+	r, err := NewRecorder(*config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := bbgrpc.NewServer(
+		bbgrpc.WithUnaryInterceptor(r.UnaryServerInterceptor()),
+		bbgrpc.WithStreamInterceptor(r.StreamServerInterceptor()),
+	)
+	s.Health.SetServingStatus("test", healthpb.HealthCheckResponse_SERVING)
+	client := healthpb.NewHealthClient(s.InProcessConn())
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"authorization", "Bearer secret",
+		"x-test-bin", "\x00\x01",
+	)
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"}); err == nil {
+		t.Fatal("expected error for unknown service")
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []*Record
+	if err := ReadRecords(f, func(record *Record) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	check := records[0]
+	if check.Method != "/grpc.health.v1.Health/Check" {
+		t.Errorf("method is %q", check.Method)
+	}
+	if check.Code != "OK" {
+		t.Errorf("code is %q, want OK", check.Code)
+	}
+	if got := check.Metadata["authorization"]; len(got) != 1 || got[0] != RedactedValue {
+		t.Errorf("authorization is %v, want redacted", got)
+	}
+	md, err := check.Metadata.MD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := md.Get("x-test-bin"); len(got) != 1 || got[0] != "\x00\x01" {
+		t.Errorf("x-test-bin is %q", got)
+	}
+	if len(check.Requests) != 1 || string(check.Requests[0]) != `{"service":"test"}` {
+		t.Errorf("requests are %s", check.Requests)
+	}
+	if len(check.Responses) != 1 || string(check.Responses[0]) != `{}` {
+		t.Errorf("responses are %s, want redacted status", check.Responses)
+	}
+	if len(check.Redacted) != 1 || check.Redacted[0] != "status" {
+		t.Errorf("redacted is %v", check.Redacted)
+	}
+
+	if unknown := records[1]; unknown.Code != "NotFound" || len(unknown.Responses) != 0 {
+		t.Errorf("code is %q with %d responses, want NotFound without responses", unknown.Code, len(unknown.Responses))
+	}
+}
+
+func TestRecorderRedactNested(t *testing.T) {
+	config := DefaultConfig()
+	config.File = filepath.Join(t.TempDir(), "recording.jsonl")
+	config.RedactFields = []string{"fields.default_value", "options.value", "source_context.file_name"}
+	r, err := NewRecorder(*config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	msg := &typepb.Type{
+		Name: "Type",
+		Fields: []*typepb.Field{
+			{Name: "first", DefaultValue: "secret"},
+			{Name: "second", DefaultValue: "secret"},
+		},
+		Options: []*typepb.Option{
+			{Name: "option"},
+		},
+	}
+	data, err := r.marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"name":"Type","fields":[{"name":"first"},{"name":"second"}],"options":[{"name":"option"}]}`; string(data) != expected {
+		t.Errorf("redacted message is %s, want %s", data, expected)
+	}
+	if msg.Fields[0].DefaultValue != "secret" {
+		t.Error("redacting modified the original message")
+	}
+
+	config.RedactFields = []string{"credentials.password"}
+	r, err = NewRecorder(*config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	req := dynamicpb.NewMessage(credentialsRequestDescriptor(t))
+	if err := protojson.Unmarshal([]byte(`{"credentials":{"a":{"user":"alice","password":"secret"}}}`), req); err != nil {
+		t.Fatal(err)
+	}
+	data, err = r.marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"credentials":{"a":{"user":"alice"}}}`; string(data) != expected {
+		t.Errorf("redacted message is %s, want %s", data, expected)
+	}
+}
+
+// credentialsRequestDescriptor returns the descriptor of a message with a map of messages.
+func credentialsRequestDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	var fdp descriptorpb.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(`
+		name: "test.proto"
+		package: "test"
+		syntax: "proto3"
+		message_type {
+			name: "Credentials"
+			field { name: "user" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "user" }
+			field { name: "password" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "password" }
+		}
+		message_type {
+			name: "Request"
+			field { name: "credentials" number: 1 type: TYPE_MESSAGE label: LABEL_REPEATED type_name: ".test.Request.CredentialsEntry" json_name: "credentials" }
+			nested_type {
+				name: "CredentialsEntry"
+				field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "key" }
+				field { name: "value" number: 2 type: TYPE_MESSAGE label: LABEL_OPTIONAL type_name: ".test.Credentials" json_name: "value" }
+				options { map_entry: true }
+			}
+		}
+	`), &fdp); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(&fdp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Messages().ByName("Request")
+}
+
+func TestRotatingFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "recording.jsonl")
+	f, err := openRotatingFile(name, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		name:        "fourth\n",
+		name + ".1": "third\n",
+		name + ".2": "second\n",
+	} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); got != want {
+			t.Errorf("%s contains %q, want %q", filepath.Base(name), got, want)
+		}
+	}
+	if _, err := os.Stat(name + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 exists", filepath.Base(name))
+	}
+}
@@ -0,0 +1,336 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// WithTranscoding makes the server add gRPC-gateway routes for the google.api.http
+// annotations of services that are registered with RegisterService, so that no
+// generated gateway code is needed. The service descriptors are looked up in the
+// global registry of the protobuf runtime.
+func WithTranscoding() Option {
+	return option(func(o *options) {
+		o.transcoding = true
+	})
+}
+
+// TranscodeRegisteredServices adds gRPC-gateway routes for the google.api.http
+// annotations of all services that are registered to the gRPC server.
+// Services that are not in the global registry of the protobuf runtime are skipped.
+func (s *Server) TranscodeRegisteredServices() error {
+	for name := range s.Server.GetServiceInfo() {
+		if service, ok := findService(name); ok {
+			if err := s.Transcode(service); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func findService(name string) (protoreflect.ServiceDescriptor, bool) {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, false
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	return service, ok
+}
+
+// TranscodeDescriptorSet adds gRPC-gateway routes for the google.api.http annotations
+// of all services in a serialized FileDescriptorSet, such as the one written by
+// protoc --descriptor_set_out. Imports that are not in the set are looked up in the
+// global registry of the protobuf runtime.
+func (s *Server) TranscodeDescriptorSet(data []byte) error {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("transcoding: invalid descriptor set: %w", err)
+	}
+	resolver := &descriptorSetResolver{files: new(protoregistry.Files)}
+	for _, fileProto := range set.GetFile() {
+		file, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fileProto, resolver)
+		if err != nil {
+			return fmt.Errorf("transcoding: invalid file %q in descriptor set: %w", fileProto.GetName(), err)
+		}
+		if err := resolver.files.RegisterFile(file); err != nil {
+			return fmt.Errorf("transcoding: invalid file %q in descriptor set: %w", fileProto.GetName(), err)
+		}
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			if err := s.Transcode(services.Get(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// descriptorSetResolver resolves the files of a descriptor set,
+// and falls back to the global registry for imports.
+type descriptorSetResolver struct {
+	files *protoregistry.Files
+}
+
+func (r *descriptorSetResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if file, err := r.files.FindFileByPath(path); err == nil {
+		return file, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r *descriptorSetResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if desc, err := r.files.FindDescriptorByName(name); err == nil {
+		return desc, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// Transcode adds gRPC-gateway routes for the google.api.http annotations of the
+// given services. Requests are handled over the InProcessConn of the server.
+//
+// Unary and server streaming methods are supported. A body or response_body
+// that selects a single field is only supported for message fields.
+func (s *Server) Transcode(services ...protoreflect.ServiceDescriptor) error {
+	for _, service := range services {
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			if method.IsStreamingClient() {
+				continue
+			}
+			rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+			if !ok || rule == nil {
+				continue
+			}
+			for _, rule := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+				if err := s.transcodeRule(method, rule); err != nil {
+					return fmt.Errorf("transcoding: invalid http rule for %s: %w", method.FullName(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) transcodeRule(method protoreflect.MethodDescriptor, rule *annotations.HttpRule) error {
+	var httpMethod, pattern string
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		httpMethod, pattern = http.MethodGet, p.Get
+	case *annotations.HttpRule_Put:
+		httpMethod, pattern = http.MethodPut, p.Put
+	case *annotations.HttpRule_Post:
+		httpMethod, pattern = http.MethodPost, p.Post
+	case *annotations.HttpRule_Delete:
+		httpMethod, pattern = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		httpMethod, pattern = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Custom:
+		httpMethod, pattern = p.Custom.GetKind(), p.Custom.GetPath()
+	default:
+		return fmt.Errorf("no pattern")
+	}
+	h := &transcoder{
+		server:       s,
+		method:       method,
+		fullMethod:   fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name()),
+		pattern:      pattern,
+		body:         rule.GetBody(),
+		responseBody: rule.GetResponseBody(),
+	}
+	if h.body != "" && h.body != "*" {
+		if field := method.Input().Fields().ByName(protoreflect.Name(h.body)); field == nil || field.Message() == nil || field.IsList() || field.IsMap() {
+			return fmt.Errorf("body %q is not a message field of %s", h.body, method.Input().FullName())
+		}
+	}
+	if h.responseBody != "" {
+		if field := method.Output().Fields().ByName(protoreflect.Name(h.responseBody)); field == nil {
+			return fmt.Errorf("response body %q is not a field of %s", h.responseBody, method.Output().FullName())
+		}
+	}
+	return s.Gateway.HandlePath(httpMethod, pattern, h.handle)
+}
+
+type transcoder struct {
+	server       *Server
+	method       protoreflect.MethodDescriptor
+	fullMethod   string
+	pattern      string
+	body         string
+	responseBody string
+}
+
+// newMessage returns a message of the generated type if it is linked into the binary,
+// or a dynamic message otherwise.
+func newMessage(desc protoreflect.MessageDescriptor) proto.Message {
+	if messageType, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName()); err == nil {
+		return messageType.New().Interface()
+	}
+	return dynamicpb.NewMessage(desc)
+}
+
+func (h *transcoder) handle(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	mux := h.server.Gateway
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	inbound, outbound := runtime.MarshalerForRequest(mux, r)
+	ctx, err := runtime.AnnotateContext(ctx, mux, r, h.fullMethod, runtime.WithHTTPPathPattern(h.pattern))
+	if err != nil {
+		runtime.HTTPError(ctx, mux, outbound, w, r, err)
+		return
+	}
+	req, err := h.decodeRequest(r, inbound, pathParams)
+	if err != nil {
+		runtime.HTTPError(ctx, mux, outbound, w, r, err)
+		return
+	}
+	conn := h.server.InProcessConn()
+	if h.method.IsStreamingServer() {
+		h.handleStream(ctx, conn, w, r, outbound, req)
+		return
+	}
+	var md runtime.ServerMetadata
+	res := newMessage(h.method.Output())
+	err = conn.Invoke(ctx, h.fullMethod, req, res, grpc.Header(&md.HeaderMD), grpc.Trailer(&md.TrailerMD))
+	ctx = runtime.NewServerMetadataContext(ctx, md)
+	if err != nil {
+		runtime.HTTPError(ctx, mux, outbound, w, r, err)
+		return
+	}
+	runtime.ForwardResponseMessage(ctx, mux, outbound, w, r, h.wrapResponse(res))
+}
+
+func (h *transcoder) handleStream(ctx context.Context, conn grpc.ClientConnInterface, w http.ResponseWriter, r *http.Request, outbound runtime.Marshaler, req proto.Message) {
+	mux := h.server.Gateway
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, h.fullMethod)
+	if err == nil {
+		err = stream.SendMsg(req)
+	}
+	if err == nil {
+		err = stream.CloseSend()
+	}
+	var md runtime.ServerMetadata
+	if err == nil {
+		md.HeaderMD, err = stream.Header()
+	}
+	if err != nil {
+		runtime.HTTPError(ctx, mux, outbound, w, r, err)
+		return
+	}
+	ctx = runtime.NewServerMetadataContext(ctx, md)
+	runtime.ForwardResponseStream(ctx, mux, outbound, w, r, func() (proto.Message, error) {
+		res := newMessage(h.method.Output())
+		if err := stream.RecvMsg(res); err != nil {
+			return nil, err
+		}
+		return h.wrapResponse(res), nil
+	})
+}
+
+func (h *transcoder) decodeRequest(r *http.Request, inbound runtime.Marshaler, pathParams map[string]string) (proto.Message, error) {
+	req := newMessage(h.method.Input())
+	switch h.body {
+	case "":
+	case "*":
+		if err := inbound.NewDecoder(r.Body).Decode(req); err != nil && err != io.EOF {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	default:
+		msg := req.ProtoReflect()
+		field := msg.Descriptor().Fields().ByName(protoreflect.Name(h.body))
+		if err := inbound.NewDecoder(r.Body).Decode(msg.Mutable(field).Message().Interface()); err != nil && err != io.EOF {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+	filter := make([][]string, 0, len(pathParams)+1)
+	for fieldPath, value := range pathParams {
+		if err := runtime.PopulateFieldFromPath(req, fieldPath, value); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: %s, error: %v", fieldPath, err)
+		}
+		filter = append(filter, strings.Split(fieldPath, "."))
+	}
+	if h.body != "*" {
+		if h.body != "" {
+			filter = append(filter, []string{h.body})
+		}
+		if err := r.ParseForm(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		if err := runtime.PopulateQueryParameters(req, r.Form, utilities.NewDoubleArray(filter)); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+	return req, nil
+}
+
+// wrapResponse wraps res so that the gRPC-gateway only marshals the response_body field.
+func (h *transcoder) wrapResponse(res proto.Message) proto.Message {
+	if h.responseBody == "" {
+		return res
+	}
+	return &responseBody{Message: res, field: protoreflect.Name(h.responseBody)}
+}
+
+type responseBody struct {
+	proto.Message
+	field protoreflect.Name
+}
+
+func (r *responseBody) XXX_ResponseBody() interface{} {
+	msg := r.ProtoReflect()
+	field := msg.Descriptor().Fields().ByName(r.field)
+	return goValue(field, msg.Get(field))
+}
+
+// goValue converts a protoreflect value to a value that the gRPC-gateway marshalers understand.
+func goValue(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch {
+	case field.IsList():
+		list := value.List()
+		values := make([]interface{}, list.Len())
+		for i := range values {
+			values[i] = goScalarValue(field, list.Get(i))
+		}
+		return values
+	case field.IsMap():
+		values := make(map[string]interface{}, value.Map().Len())
+		value.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+			values[key.String()] = goScalarValue(field.MapValue(), value)
+			return true
+		})
+		return values
+	default:
+		return goScalarValue(field, value)
+	}
+}
+
+func goScalarValue(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return value.Message().Interface()
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return int32(value.Enum())
+	default:
+		return value.Interface()
+	}
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+	featureflag "htdvisser.dev/exp/backbone/api/featureflag/v1alpha1"
+)
+
+type testFeatureFlagService struct {
+	featureflag.UnimplementedFeatureFlagServiceServer
+}
+
+func (testFeatureFlagService) ListFlags(context.Context, *featureflag.ListFlagsRequest) (*featureflag.ListFlagsResponse, error) {
+	return &featureflag.ListFlagsResponse{Flags: []*featureflag.Flag{{Name: "test", Enabled: true}}}, nil
+}
+
+func (testFeatureFlagService) SetOverride(_ context.Context, req *featureflag.SetOverrideRequest) (*featureflag.Flag, error) {
+	return &featureflag.Flag{Name: req.GetName(), Override: req.GetOverride()}, nil
+}
+
+func testTranscoding(t *testing.T, s *Server) {
+	t.Helper()
+	srv := httptest.NewServer(s.Gateway)
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		method, path, body string
+		want               string
+	}{
+		{http.MethodGet, "/featureflag/v1alpha1/flags", "", `"name":"test"`},
+		{http.MethodPost, "/featureflag/v1alpha1/flags/foo/override", `{"override":"ON"}`, `"override":"ON"`},
+		{http.MethodPost, "/featureflag/v1alpha1/flags/foo/override", `{"override":"ON"}`, `"name":"foo"`},
+		{http.MethodPost, "/featureflag/v1alpha1/flags/foo/evaluate", `{}`, `"code":12`},
+	} {
+		req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if !strings.Contains(string(body), tt.want) {
+			t.Errorf("%s %s returned %s, want %s", tt.method, tt.path, body, tt.want)
+		}
+	}
+}
+
+func TestTranscoding(t *testing.T) {
+	s := NewServer(WithTranscoding())
+	featureflag.RegisterFeatureFlagServiceServer(s, testFeatureFlagService{})
+	testTranscoding(t, s)
+}
+
+func TestTranscodeDescriptorSet(t *testing.T) {
+	data, err := os.ReadFile("../../api/featureflag/v1alpha1/featureflag.pb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer()
+	featureflag.RegisterFeatureFlagServiceServer(s, testFeatureFlagService{})
+	if err := s.TranscodeDescriptorSet(data); err != nil {
+		t.Fatal(err)
+	}
+	testTranscoding(t, s)
+}
+
+func TestCloneIntoDynamicMessage(t *testing.T) {
+	src := &featureflag.Flag{Name: "test", Override: featureflag.Flag_ON}
+	dynamic := dynamicpb.NewMessage(src.ProtoReflect().Descriptor())
+	if err := cloneInto(dynamic, src); err != nil {
+		t.Fatal(err)
+	}
+	var dst featureflag.Flag
+	if err := cloneInto(&dst, dynamic); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&dst, src) {
+		t.Errorf("cloned message is %v, want %v", &dst, src)
+	}
+}
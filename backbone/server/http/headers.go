@@ -0,0 +1,103 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"time"
+)
+
+// SecurityHeaders is the configuration for security headers.
+// Empty fields are not set in responses.
+type SecurityHeaders struct {
+	// HSTSMaxAge is the max-age of the Strict-Transport-Security header.
+	// The header is only set on responses to requests over HTTPS.
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	// TrustedProxies are the networks of proxies that are trusted to set the
+	// X-Forwarded-Proto header. The header is ignored on requests from other addresses.
+	TrustedProxies []netip.Prefix
+	// ContentSecurityPolicy is the value of the Content-Security-Policy header.
+	ContentSecurityPolicy string
+	// FrameOptions is the value of the X-Frame-Options header.
+	FrameOptions string
+	// ContentTypeOptions is the value of the X-Content-Type-Options header.
+	ContentTypeOptions string
+	// ReferrerPolicy is the value of the Referrer-Policy header.
+	ReferrerPolicy string
+}
+
+// DefaultSecurityHeaders returns the default security headers.
+func DefaultSecurityHeaders() *SecurityHeaders {
+	return &SecurityHeaders{
+		HSTSMaxAge:            2 * 365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentSecurityPolicy: "default-src 'self'; frame-ancestors 'none'",
+		FrameOptions:          "DENY",
+		ContentTypeOptions:    "nosniff",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	}
+}
+
+func isTrustedProxy(r *http.Request, trustedProxies []netip.Prefix) bool {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTPS(r *http.Request, trustedProxies []netip.Prefix) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return r.Header.Get("X-Forwarded-Proto") == "https" && isTrustedProxy(r, trustedProxies)
+}
+
+// Middleware returns middleware that sets the security headers on responses.
+// Handlers can override the headers by setting them before writing the response.
+// To use different security headers for specific routes, add the middleware to
+// those routes of the Router.
+func (h *SecurityHeaders) Middleware() Middleware {
+	var hsts string
+	if h.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d", int64(h.HSTSMaxAge.Seconds()))
+		if h.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+	headers := make(http.Header)
+	for key, value := range map[string]string{
+		"Content-Security-Policy": h.ContentSecurityPolicy,
+		"X-Frame-Options":         h.FrameOptions,
+		"X-Content-Type-Options":  h.ContentTypeOptions,
+		"Referrer-Policy":         h.ReferrerPolicy,
+	} {
+		if value != "" {
+			headers.Set(key, value)
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for key := range headers {
+				w.Header().Set(key, headers.Get(key))
+			}
+			if hsts != "" && isHTTPS(r, h.TrustedProxies) {
+				w.Header().Set("Strict-Transport-Security", hsts)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithSecurityHeaders returns an option that adds middleware that sets the
+// security headers on all responses of the server.
+func WithSecurityHeaders(headers *SecurityHeaders) Option {
+	return WithMiddleware(headers.Middleware())
+}
@@ -20,13 +20,18 @@ type Server struct {
 	contextExtenders []func(context.Context) context.Context
 	middleware       []Middleware
 	chain            http.Handler
+	maxBodyBytes     int64
 }
 
 // NewServer instantiates a new HTTP server with the given options.
 func NewServer(opts ...Option) *Server {
 	options := &options{
-		serveMux: http.NewServeMux(),
-		router:   mux.NewRouter(),
+		serveMux:          http.NewServeMux(),
+		router:            mux.NewRouter(),
+		readHeaderTimeout: DefaultReadHeaderTimeout,
+		idleTimeout:       DefaultIdleTimeout,
+		maxHeaderBytes:    DefaultMaxHeaderBytes,
+		maxBodyBytes:      DefaultMaxBodyBytes,
 	}
 	options.apply(opts...)
 	s := &Server{
@@ -34,21 +39,30 @@ func NewServer(opts ...Option) *Server {
 		Router:           options.router,
 		contextExtenders: options.contextExtenders,
 		middleware:       options.middleware,
+		maxBodyBytes:     options.maxBodyBytes,
 	}
 	s.chain = chain(s.ServeMux, s.middleware...)
 	s.ServeMux.Handle("/", s.Router)
 	var handler http.Handler = s
 	if options.h2c {
-		s.http2server = &http2.Server{}
+		s.http2server = &http2.Server{IdleTimeout: options.idleTimeout}
 		if s.http2server != nil {
 			handler = h2c.NewHandler(s, s.http2server)
 		}
 	}
-	s.server = &http.Server{Handler: handler}
+	s.server = &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: options.readHeaderTimeout,
+		ReadTimeout:       options.readTimeout,
+		WriteTimeout:      options.writeTimeout,
+		IdleTimeout:       options.idleTimeout,
+		MaxHeaderBytes:    options.maxHeaderBytes,
+	}
 	return s
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r = s.limitBody(w, r)
 	s.chain.ServeHTTP(w, s.extendContext(r))
 }
 
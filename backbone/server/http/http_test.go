@@ -0,0 +1,90 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestLimitsAndSecurityHeaders(t *testing.T) {
+	s := NewServer(
+		WithMaxBodyBytes(8),
+		WithSecurityHeaders(DefaultSecurityHeaders()),
+	)
+	s.ServeMux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	})
+
+	for body, expected := range map[string]int{
+		"small":            http.StatusOK,
+		"way too large...": http.StatusRequestEntityTooLarge,
+	} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body)))
+		if rec.Code != expected {
+			t.Errorf("upload of %q returned %d, expected %d", body, rec.Code, expected)
+		}
+		if rec.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Errorf("security headers not set: %v", rec.Header())
+		}
+		if rec.Header().Get("Strict-Transport-Security") != "" {
+			t.Errorf("HSTS header set on plain HTTP request")
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("way too large..."))
+	req.Header.Set("Content-Type", "application/grpc")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("upload with gRPC content type returned %d, expected %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	s.ServeMux.HandleFunc("/unlimited", func(w http.ResponseWriter, r *http.Request) {
+		RemoveBodyLimit(r)
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	})
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/unlimited", strings.NewReader("way too large...")))
+	if rec.Code != http.StatusOK {
+		t.Errorf("upload without body limit returned %d, expected %d", rec.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "https://example.com/upload", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Header().Get("Strict-Transport-Security") != "max-age=63072000; includeSubDomains" {
+		t.Errorf("unexpected HSTS header %q", rec.Header().Get("Strict-Transport-Security"))
+	}
+
+	headers := DefaultSecurityHeaders()
+	headers.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	s = NewServer(WithSecurityHeaders(headers))
+	for remoteAddr, expected := range map[string]bool{
+		"10.1.2.3:1234":          true,
+		"[::ffff:10.1.2.3]:1234": true,
+		"192.0.2.1:1234":         false,
+		"[2001:db8::1]:1234":     false,
+		"not-an-address":         false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-Proto", "https")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if actual := rec.Header().Get("Strict-Transport-Security") != ""; actual != expected {
+			t.Errorf("HSTS header for forwarded request from %s was set: %v, expected %v", remoteAddr, actual, expected)
+		}
+	}
+
+	if s.server.ReadHeaderTimeout != DefaultReadHeaderTimeout {
+		t.Errorf("read header timeout was %s, expected %s", s.server.ReadHeaderTimeout, DefaultReadHeaderTimeout)
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Default timeouts and limits of the HTTP server.
+const (
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultIdleTimeout       = 2 * time.Minute
+	DefaultMaxHeaderBytes    = 1 << 20 // 1 MiB
+	DefaultMaxBodyBytes      = 4 << 20 // 4 MiB
+)
+
+// WithReadHeaderTimeout returns an option that sets the amount of time allowed
+// to read request headers. This protects against Slowloris attacks.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return option(func(opts *options) {
+		opts.readHeaderTimeout = timeout
+	})
+}
+
+// WithReadTimeout returns an option that sets the maximum duration for reading
+// entire requests, including the body. The default is no timeout.
+func WithReadTimeout(timeout time.Duration) Option {
+	return option(func(opts *options) {
+		opts.readTimeout = timeout
+	})
+}
+
+// WithWriteTimeout returns an option that sets the maximum duration before timing
+// out writes of responses. The default is no timeout, because a timeout breaks
+// streaming responses (such as gRPC-Web and WebSockets).
+func WithWriteTimeout(timeout time.Duration) Option {
+	return option(func(opts *options) {
+		opts.writeTimeout = timeout
+	})
+}
+
+// WithIdleTimeout returns an option that sets the maximum amount of time to wait
+// for the next request when keep-alives are enabled.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return option(func(opts *options) {
+		opts.idleTimeout = timeout
+	})
+}
+
+// WithMaxHeaderBytes returns an option that sets the maximum number of bytes
+// the server reads parsing request headers.
+func WithMaxHeaderBytes(maxHeaderBytes int) Option {
+	return option(func(opts *options) {
+		opts.maxHeaderBytes = maxHeaderBytes
+	})
+}
+
+// WithMaxBodyBytes returns an option that sets the maximum number of bytes the
+// server reads from request bodies (0 for no limit). Handlers that limit the size
+// of requests themselves, such as the gRPC server, can remove the limit with
+// RemoveBodyLimit.
+func WithMaxBodyBytes(maxBodyBytes int64) Option {
+	return option(func(opts *options) {
+		opts.maxBodyBytes = maxBodyBytes
+	})
+}
+
+type limitedBody struct {
+	original io.ReadCloser
+	limited  io.ReadCloser
+}
+
+type limitedBodyCtxKeyType struct{}
+
+var limitedBodyCtxKey limitedBodyCtxKeyType
+
+func (s *Server) limitBody(w http.ResponseWriter, r *http.Request) *http.Request {
+	if s.maxBodyBytes <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return r
+	}
+	body := &limitedBody{
+		original: r.Body,
+		limited:  http.MaxBytesReader(w, r.Body, s.maxBodyBytes),
+	}
+	r = r.WithContext(context.WithValue(r.Context(), limitedBodyCtxKey, body))
+	r.Body = body.limited
+	return r
+}
+
+// RemoveBodyLimit removes the limit of WithMaxBodyBytes from the body of the request.
+// The limit is not removed if the body was replaced after the server received the request.
+func RemoveBodyLimit(r *http.Request) {
+	if body, ok := r.Context().Value(limitedBodyCtxKey).(*limitedBody); ok && r.Body == body.limited {
+		r.Body = body.original
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -13,6 +14,13 @@ type options struct {
 	h2c              bool
 	contextExtenders []func(context.Context) context.Context
 	middleware       []Middleware
+
+	readHeaderTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	maxHeaderBytes    int
+	maxBodyBytes      int64
 }
 
 func (o *options) apply(opts ...Option) {
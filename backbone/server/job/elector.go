@@ -0,0 +1,151 @@
+package job
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-redis/redis/v8"
+)
+
+// Elector elects a leader between replicas.
+type Elector interface {
+	// Run takes part in the election until ctx is done.
+	Run(ctx context.Context) error
+	// Leader returns a context that is done when this replica loses the leadership,
+	// and whether this replica is the leader.
+	Leader() (context.Context, bool)
+}
+
+type alwaysLeader struct{}
+
+// AlwaysLeader is an Elector for services that run a single replica.
+var AlwaysLeader Elector = alwaysLeader{}
+
+func (alwaysLeader) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (alwaysLeader) Leader() (context.Context, bool) {
+	return context.Background(), true
+}
+
+// DefaultLeaseDuration is the default duration of the leader lease.
+const DefaultLeaseDuration = 15 * time.Second
+
+var (
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// RedisElector is an Elector that elects the replica that holds a lock in Redis.
+// The leader renews the lock at a third of the lease duration. If it fails to
+// renew the lock, it steps down.
+type RedisElector struct {
+	client redis.UniversalClient
+	key    string
+	lease  time.Duration
+	clock  clock.Clock
+	token  string
+
+	mu        sync.Mutex
+	leaderCtx context.Context
+	stepDown  context.CancelFunc
+}
+
+// NewRedisElector returns a new Elector that uses a lock on the given key in Redis.
+// Use redisconfig to connect to Redis. If lease is zero, DefaultLeaseDuration is used.
+func NewRedisElector(client redis.UniversalClient, key string, lease time.Duration, opts ...Option) *RedisElector {
+	options := newOptions(opts...)
+	if lease == 0 {
+		lease = DefaultLeaseDuration
+	}
+	var token [16]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		panic(err)
+	}
+	return &RedisElector{
+		client: client,
+		key:    key,
+		lease:  lease,
+		clock:  options.clock,
+		token:  hex.EncodeToString(token[:]),
+	}
+}
+
+// Leader implements Elector.
+func (e *RedisElector) Leader() (context.Context, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.leaderCtx == nil {
+		return nil, false
+	}
+	return e.leaderCtx, true
+}
+
+func (e *RedisElector) acquireOrRenew(ctx context.Context) (bool, error) {
+	if _, leader := e.Leader(); leader {
+		renewed, err := renewScript.Run(ctx, e.client, []string{e.key}, e.token, e.lease.Milliseconds()).Int()
+		return renewed == 1, err
+	}
+	return e.client.SetNX(ctx, e.key, e.token, e.lease).Result()
+}
+
+func (e *RedisElector) setLeader(leader bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch {
+	case leader && e.leaderCtx == nil:
+		e.leaderCtx, e.stepDown = context.WithCancel(context.Background())
+		log.Printf("Became leader for %q", e.key)
+	case !leader && e.leaderCtx != nil:
+		e.stepDown()
+		e.leaderCtx, e.stepDown = nil, nil
+		log.Printf("Stepped down as leader for %q", e.key)
+	}
+}
+
+// Run implements Elector.
+func (e *RedisElector) Run(ctx context.Context) error {
+	defer func() {
+		if _, leader := e.Leader(); leader {
+			e.setLeader(false)
+			// The context is already canceled, but we want to release the lock
+			// so that another replica can take over right away.
+			releaseCtx, cancel := context.WithTimeout(context.Background(), e.lease/3)
+			defer cancel()
+			releaseScript.Run(releaseCtx, e.client, []string{e.key}, e.token)
+		}
+	}()
+	ticker := e.clock.Ticker(e.lease / 3)
+	defer ticker.Stop()
+	for {
+		leader, err := e.acquireOrRenew(ctx)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Printf("Failed to acquire or renew leadership for %q: %v", e.key, err)
+		}
+		e.setLeader(leader)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
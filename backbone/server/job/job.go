@@ -0,0 +1,289 @@
+// Package job runs background jobs on a schedule while the server runs.
+//
+// Singleton jobs run on only one replica: the leader that is elected by an Elector.
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"golang.org/x/sync/errgroup"
+	"htdvisser.dev/exp/backbone/server"
+)
+
+// Job is a background job.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Jitter is the maximum random delay that is added to each scheduled run.
+	Jitter time.Duration
+	// Timeout is the maximum duration of a run. Zero means no timeout.
+	Timeout time.Duration
+	// Singleton indicates that the job only runs on the leader.
+	Singleton bool
+	// Run runs the job. The context is canceled when the server shuts down,
+	// and (for singleton jobs) when the replica loses the leadership.
+	Run func(ctx context.Context) error
+}
+
+type options struct {
+	clock   clock.Clock
+	elector Elector
+}
+
+func newOptions(opts ...Option) *options {
+	options := &options{
+		clock:   clock.New(),
+		elector: AlwaysLeader,
+	}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	return options
+}
+
+// Option is an option for the Runner or Elector.
+type Option interface {
+	apply(*options)
+}
+
+type option func(*options)
+
+func (f option) apply(opts *options) {
+	f(opts)
+}
+
+// WithClock returns an option that sets the clock.
+func WithClock(clock clock.Clock) Option {
+	return option(func(o *options) {
+		o.clock = clock
+	})
+}
+
+// WithElector returns an option that sets the Elector for singleton jobs.
+// The default is AlwaysLeader.
+func WithElector(elector Elector) Option {
+	return option(func(o *options) {
+		o.elector = elector
+	})
+}
+
+// Status is the status of a job.
+type Status struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Singleton bool      `json:"singleton,omitempty"`
+	Running   bool      `json:"running"`
+	NextRun   time.Time `json:"next_run,omitempty"`
+	LastStart time.Time `json:"last_start,omitempty"`
+	LastEnd   time.Time `json:"last_end,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	Runs      int       `json:"runs"`
+	Failures  int       `json:"failures"`
+	// Skipped is the number of scheduled runs that were skipped because
+	// the replica was not the leader, or because the previous run was still running.
+	Skipped int `json:"skipped"`
+}
+
+type job struct {
+	Job
+
+	mu     sync.Mutex
+	status Status
+}
+
+// Runner runs jobs.
+type Runner struct {
+	clock   clock.Clock
+	elector Elector
+
+	mu   sync.Mutex
+	jobs []*job
+}
+
+// NewRunner returns a new job runner.
+func NewRunner(opts ...Option) *Runner {
+	options := newOptions(opts...)
+	return &Runner{
+		clock:   options.clock,
+		elector: options.elector,
+	}
+}
+
+// Add adds a job to the runner. Jobs must be added before the runner runs.
+func (r *Runner) Add(j Job) error {
+	if j.Name == "" || j.Schedule == nil || j.Run == nil {
+		return fmt.Errorf("job: job %q needs a name, schedule and run func", j.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.jobs {
+		if existing.Name == j.Name {
+			return fmt.Errorf("job: job %q already added", j.Name)
+		}
+	}
+	r.jobs = append(r.jobs, &job{
+		Job: j,
+		status: Status{
+			Name:      j.Name,
+			Schedule:  j.Schedule.String(),
+			Singleton: j.Singleton,
+		},
+	})
+	return nil
+}
+
+// Statuses returns the status of the jobs.
+func (r *Runner) Statuses() []Status {
+	r.mu.Lock()
+	jobs := r.jobs
+	r.mu.Unlock()
+	statuses := make([]Status, len(jobs))
+	for i, j := range jobs {
+		j.mu.Lock()
+		statuses[i] = j.status
+		j.mu.Unlock()
+	}
+	return statuses
+}
+
+// Run runs the elector and the jobs until ctx is done,
+// and then waits for running jobs to return.
+func (r *Runner) Run(ctx context.Context) error {
+	r.mu.Lock()
+	jobs := r.jobs
+	r.mu.Unlock()
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error { return r.elector.Run(ctx) })
+	for _, j := range jobs {
+		j := j
+		g.Go(func() error { return r.runJob(ctx, j) })
+	}
+	return g.Wait()
+}
+
+func (r *Runner) runJob(ctx context.Context, j *job) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	var running sync.Mutex
+	now := r.clock.Now()
+	for {
+		next := j.Schedule.Next(now)
+		if next.IsZero() {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		if j.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(j.Jitter))))
+		}
+		j.mu.Lock()
+		j.status.NextRun = next
+		j.mu.Unlock()
+		timer := r.clock.Timer(next.Sub(r.clock.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case now = <-timer.C:
+		}
+		runCtx, cancel, ok := r.runContext(ctx, j)
+		if !ok || !running.TryLock() {
+			if ok {
+				cancel()
+			}
+			j.mu.Lock()
+			j.status.Skipped++
+			j.mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer running.Unlock()
+			defer cancel()
+			r.run(runCtx, j)
+		}()
+	}
+}
+
+// runContext returns the context for a run of the job, and whether the job should run.
+func (r *Runner) runContext(ctx context.Context, j *job) (context.Context, context.CancelFunc, bool) {
+	var cancel context.CancelFunc
+	if j.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	if !j.Singleton {
+		return ctx, cancel, true
+	}
+	leaderCtx, leader := r.elector.Leader()
+	if !leader {
+		cancel()
+		return nil, nil, false
+	}
+	go func() {
+		select {
+		case <-leaderCtx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel, true
+}
+
+func (r *Runner) run(ctx context.Context, j *job) {
+	j.mu.Lock()
+	j.status.Running = true
+	j.status.LastStart = r.clock.Now()
+	j.mu.Unlock()
+
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v\n%s", p, debug.Stack())
+			}
+		}()
+		return j.Run(ctx)
+	}()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Running = false
+	j.status.LastEnd = r.clock.Now()
+	j.status.Runs++
+	j.status.LastError = ""
+	if err != nil {
+		j.status.Failures++
+		j.status.LastError = err.Error()
+		log.Printf("Job %s failed: %v", j.Name, err)
+	}
+}
+
+// ServeHTTP serves the status of the jobs as JSON.
+func (r *Runner) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	_, leader := r.elector.Leader()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Leader bool     `json:"leader"`
+		Jobs   []Status `json:"jobs"`
+	}{
+		Leader: leader,
+		Jobs:   r.Statuses(),
+	})
+}
+
+// Register registers the runner as a task of the server,
+// and serves the status of the jobs on /jobs of the internal HTTP server.
+func (r *Runner) Register(s *server.Server) {
+	s.RegisterTask("jobs", r.Run)
+	s.InternalHTTP.ServeMux.Handle("/jobs", r)
+}
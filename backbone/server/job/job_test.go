@@ -0,0 +1,101 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+func TestParseSchedule(t *testing.T) {
+	base := time.Date(2023, time.December, 6, 18, 53, 58, 0, time.UTC) // Wednesday
+	for _, tt := range []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2023, time.December, 6, 18, 54, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2023, time.December, 6, 19, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2023, time.December, 7, 2, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2023, time.December, 7, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2023, time.December, 10, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2023, time.December, 15, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		schedule, err := ParseSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if next := schedule.Next(base); !next.Equal(tt.next) {
+			t.Errorf("next run of %q is %s, want %s", tt.expr, next, tt.next)
+		}
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "@every -1m"} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q) did not fail", expr)
+		}
+	}
+}
+
+type follower struct{}
+
+func (follower) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (follower) Leader() (context.Context, bool) { return nil, false }
+
+func TestRunner(t *testing.T) {
+	mock := clock.NewMock()
+	runner := NewRunner(WithClock(mock), WithElector(follower{}))
+
+	ran := make(chan struct{}, 10)
+	if err := runner.Add(Job{Name: "everywhere", Schedule: Every(time.Minute), Run: func(context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Add(Job{Name: "leader-only", Schedule: Every(time.Minute), Singleton: true, Run: func(context.Context) error {
+		t.Error("singleton job ran on follower")
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- runner.Run(ctx) }()
+
+	deadline := time.After(5 * time.Second)
+	for runs := 0; runs < 3; {
+		mock.Add(time.Minute)
+		select {
+		case <-ran:
+			runs++
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("job did not run 3 times")
+		}
+	}
+	cancel()
+	<-done
+
+	for _, status := range runner.Statuses() {
+		switch status.Name {
+		case "everywhere":
+			if status.Runs < 3 || status.Failures != 0 {
+				t.Errorf("unexpected status %+v", status)
+			}
+		case "leader-only":
+			if status.Runs != 0 || status.Skipped == 0 {
+				t.Errorf("unexpected status %+v", status)
+			}
+		}
+	}
+}
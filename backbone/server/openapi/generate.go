@@ -0,0 +1,325 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type object = map[string]interface{}
+
+// generator generates a Swagger 2.0 document from service descriptors with
+// google.api.http annotations.
+type generator struct {
+	protoNames  bool
+	paths       object
+	definitions object
+	tags        []interface{}
+}
+
+func newGenerator(protoNames bool) *generator {
+	return &generator{
+		protoNames:  protoNames,
+		paths:       make(object),
+		definitions: make(object),
+		tags:        []interface{}{},
+	}
+}
+
+func (g *generator) document() object {
+	return object{
+		"swagger":     "2.0",
+		"consumes":    []interface{}{"application/json"},
+		"produces":    []interface{}{"application/json"},
+		"tags":        g.tags,
+		"paths":       g.paths,
+		"definitions": g.definitions,
+	}
+}
+
+func (g *generator) addService(service protoreflect.ServiceDescriptor) {
+	var hasRoutes bool
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		if method.IsStreamingClient() {
+			continue
+		}
+		rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		for i, rule := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			operationID := fmt.Sprintf("%s_%s", service.Name(), method.Name())
+			if i > 0 {
+				operationID += fmt.Sprint(i + 1)
+			}
+			if g.addOperation(method, rule, operationID) {
+				hasRoutes = true
+			}
+		}
+	}
+	if hasRoutes {
+		g.tags = append(g.tags, object{"name": string(service.Name())})
+	}
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+func (g *generator) addOperation(method protoreflect.MethodDescriptor, rule *annotations.HttpRule, operationID string) bool {
+	var httpMethod, pattern string
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		httpMethod, pattern = http.MethodGet, p.Get
+	case *annotations.HttpRule_Put:
+		httpMethod, pattern = http.MethodPut, p.Put
+	case *annotations.HttpRule_Post:
+		httpMethod, pattern = http.MethodPost, p.Post
+	case *annotations.HttpRule_Delete:
+		httpMethod, pattern = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		httpMethod, pattern = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Custom:
+		httpMethod, pattern = p.Custom.GetKind(), p.Custom.GetPath()
+	default:
+		return false
+	}
+
+	input := method.Input()
+	parameters := []interface{}{}
+	exclude := make(map[string]bool)
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(pattern, -1) {
+		fieldPath := match[1]
+		exclude[fieldPath] = true
+		field := findField(input, fieldPath)
+		if field == nil {
+			continue
+		}
+		parameter := object{"name": fieldPath, "in": "path", "required": true}
+		for k, v := range g.fieldSchema(field) {
+			parameter[k] = v
+		}
+		parameters = append(parameters, parameter)
+	}
+	switch body := rule.GetBody(); body {
+	case "":
+	case "*":
+		parameters = append(parameters, object{"name": "body", "in": "body", "required": true, "schema": g.messageSchema(input)})
+	default:
+		exclude[body] = true
+		if field := input.Fields().ByName(protoreflect.Name(body)); field != nil {
+			parameters = append(parameters, object{"name": g.fieldName(field), "in": "body", "required": true, "schema": g.fieldSchema(field)})
+		}
+	}
+	if rule.GetBody() != "*" {
+		parameters = append(parameters, g.queryParameters(input, "", exclude, 0)...)
+	}
+
+	response := g.messageSchema(method.Output())
+	if responseBody := rule.GetResponseBody(); responseBody != "" {
+		if field := method.Output().Fields().ByName(protoreflect.Name(responseBody)); field != nil {
+			response = g.fieldSchema(field)
+		}
+	}
+	if method.IsStreamingServer() {
+		response = object{
+			"type":  "object",
+			"title": fmt.Sprintf("Stream result of %s", method.Output().FullName()),
+			"properties": object{
+				"result": response,
+				"error":  g.statusSchema(),
+			},
+		}
+	}
+
+	operation := object{
+		"operationId": operationID,
+		"tags":        []interface{}{string(method.Parent().Name())},
+		"parameters":  parameters,
+		"responses": object{
+			"200":     object{"description": "A successful response.", "schema": response},
+			"default": object{"description": "An unexpected error response.", "schema": g.statusSchema()},
+		},
+	}
+	path := pathParamRegexp.ReplaceAllString(pattern, "{$1}")
+	pathItem, ok := g.paths[path].(object)
+	if !ok {
+		pathItem = make(object)
+		g.paths[path] = pathItem
+	}
+	pathItem[strings.ToLower(httpMethod)] = operation
+	return true
+}
+
+func findField(msg protoreflect.MessageDescriptor, fieldPath string) protoreflect.FieldDescriptor {
+	var field protoreflect.FieldDescriptor
+	for _, name := range strings.Split(fieldPath, ".") {
+		if msg == nil {
+			return nil
+		}
+		if field = msg.Fields().ByName(protoreflect.Name(name)); field == nil {
+			return nil
+		}
+		msg = field.Message()
+	}
+	return field
+}
+
+// maxQueryDepth is the maximum depth of nested messages in query parameters.
+const maxQueryDepth = 3
+
+func (g *generator) queryParameters(msg protoreflect.MessageDescriptor, prefix string, exclude map[string]bool, depth int) []interface{} {
+	var parameters []interface{}
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fieldPath := prefix + string(field.Name())
+		if exclude[fieldPath] || field.IsMap() {
+			continue
+		}
+		if field.Message() != nil && wellKnownSchema(field.Message().FullName()) == nil {
+			if !field.IsList() && depth < maxQueryDepth {
+				parameters = append(parameters, g.queryParameters(field.Message(), fieldPath+".", exclude, depth+1)...)
+			}
+			continue
+		}
+		parameter := object{"name": prefix + g.fieldName(field), "in": "query", "required": false}
+		for k, v := range g.fieldSchema(field) {
+			parameter[k] = v
+		}
+		if field.IsList() {
+			parameter["collectionFormat"] = "multi"
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+func (g *generator) fieldName(field protoreflect.FieldDescriptor) string {
+	if g.protoNames {
+		return string(field.Name())
+	}
+	return field.JSONName()
+}
+
+func (g *generator) fieldSchema(field protoreflect.FieldDescriptor) object {
+	if field.IsMap() {
+		return object{"type": "object", "additionalProperties": g.valueSchema(field.MapValue())}
+	}
+	schema := g.valueSchema(field)
+	if field.IsList() {
+		return object{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+func (g *generator) valueSchema(field protoreflect.FieldDescriptor) object {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return object{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return object{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return object{"type": "integer", "format": "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return object{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return object{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return object{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return object{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		return object{"type": "string"}
+	case protoreflect.BytesKind:
+		return object{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]interface{}, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return object{"type": "string", "enum": names, "default": names[0]}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.messageSchema(field.Message())
+	default:
+		return object{}
+	}
+}
+
+// messageSchema returns the schema of a well-known type, or a reference to
+// the definition of the message.
+func (g *generator) messageSchema(msg protoreflect.MessageDescriptor) object {
+	if schema := wellKnownSchema(msg.FullName()); schema != nil {
+		return schema
+	}
+	name := string(msg.FullName())
+	if _, ok := g.definitions[name]; !ok {
+		definition := object{"type": "object"}
+		g.definitions[name] = definition // Before the fields, for recursive messages.
+		properties := make(object)
+		fields := msg.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			properties[g.fieldName(fields.Get(i))] = g.fieldSchema(fields.Get(i))
+		}
+		definition["properties"] = properties
+	}
+	return object{"$ref": "#/definitions/" + name}
+}
+
+func (g *generator) statusSchema() object {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName("google.rpc.Status")
+	if err != nil {
+		return object{"type": "object"}
+	}
+	return g.messageSchema(desc.(protoreflect.MessageDescriptor))
+}
+
+// wellKnownSchema returns the schema for the JSON representation of well-known types,
+// or nil if the message is not a well-known type.
+func wellKnownSchema(name protoreflect.FullName) object {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return object{"type": "string", "format": "date-time"}
+	case "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return object{"type": "string"}
+	case "google.protobuf.Empty", "google.protobuf.Struct":
+		return object{"type": "object"}
+	case "google.protobuf.Value":
+		return object{}
+	case "google.protobuf.ListValue":
+		return object{"type": "array", "items": object{}}
+	case "google.protobuf.Any":
+		return object{
+			"type":                 "object",
+			"properties":           object{"@type": object{"type": "string"}},
+			"additionalProperties": object{},
+		}
+	case "google.protobuf.BoolValue":
+		return object{"type": "boolean"}
+	case "google.protobuf.Int32Value":
+		return object{"type": "integer", "format": "int32"}
+	case "google.protobuf.UInt32Value":
+		return object{"type": "integer", "format": "int64"}
+	case "google.protobuf.Int64Value":
+		return object{"type": "string", "format": "int64"}
+	case "google.protobuf.UInt64Value":
+		return object{"type": "string", "format": "uint64"}
+	case "google.protobuf.FloatValue":
+		return object{"type": "number", "format": "float"}
+	case "google.protobuf.DoubleValue":
+		return object{"type": "number", "format": "double"}
+	case "google.protobuf.StringValue":
+		return object{"type": "string"}
+	case "google.protobuf.BytesValue":
+		return object{"type": "string", "format": "byte"}
+	default:
+		return nil
+	}
+}
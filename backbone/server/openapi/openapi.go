@@ -0,0 +1,296 @@
+// Package openapi serves OpenAPI documents for the gRPC-gateway of a backbone server.
+//
+// The documents combine Swagger 2.0 documents that are embedded in the binary
+// (such as the ones generated by protoc-gen-openapiv2) with documents that are
+// generated from the google.api.http annotations of the registered services.
+// They are served as Swagger 2.0 on v2.json and as OpenAPI 3.0 on v3.json.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"htdvisser.dev/exp/backbone/server"
+)
+
+type options struct {
+	prefix     string
+	basePath   string
+	title      string
+	version    string
+	documents  [][]byte
+	protoNames bool
+	ui         UI
+	assetsURL  string
+}
+
+// Option is an option for the OpenAPI handler.
+type Option interface {
+	apply(*options)
+}
+
+type option func(*options)
+
+func (f option) apply(opts *options) {
+	f(opts)
+}
+
+// WithPrefix returns an option that sets the path prefix under which the handler
+// is registered. The default is "/openapi".
+func WithPrefix(prefix string) Option {
+	return option(func(opts *options) {
+		opts.prefix = "/" + strings.Trim(prefix, "/")
+	})
+}
+
+// WithBasePath returns an option that sets the path prefix under which the
+// gRPC-gateway is served, such as "/api".
+func WithBasePath(basePath string) Option {
+	return option(func(opts *options) {
+		opts.basePath = "/" + strings.Trim(basePath, "/")
+	})
+}
+
+// WithInfo returns an option that sets the title and version of the API.
+func WithInfo(title, version string) Option {
+	return option(func(opts *options) {
+		opts.title, opts.version = title, version
+	})
+}
+
+// WithDocument returns an option that adds a Swagger 2.0 document in JSON format,
+// such as a *.swagger.json file that is embedded in the binary. Operations and
+// definitions in these documents replace the ones that are generated from descriptors.
+func WithDocument(document []byte) Option {
+	return option(func(opts *options) {
+		opts.documents = append(opts.documents, document)
+	})
+}
+
+// WithProtoNames returns an option that uses the field names of the proto files in
+// generated documents, instead of their lowerCamelCase JSON names. This should match
+// the UseProtoNames marshal option of the gRPC-gateway.
+func WithProtoNames(protoNames bool) Option {
+	return option(func(opts *options) {
+		opts.protoNames = protoNames
+	})
+}
+
+// WithUI returns an option that serves a UI for exploring the API on the prefix.
+// The scripts and styles of the UI are loaded from assetsURL, which must serve the
+// files of the npm packages of the UI, such as "https://unpkg.com" or a URL under
+// which the files are hosted by the server itself. Browsers run these scripts on
+// the origin of the API, so assetsURL must be trusted.
+func WithUI(ui UI, assetsURL string) Option {
+	return option(func(opts *options) {
+		opts.ui = ui
+		opts.assetsURL = strings.TrimSuffix(assetsURL, "/")
+	})
+}
+
+// Handler serves the OpenAPI documents.
+type Handler struct {
+	server  *grpc.Server
+	options *options
+
+	once sync.Once
+	v2   []byte
+	v3   []byte
+	err  error
+}
+
+// NewHandler returns a new handler that serves the OpenAPI documents for the
+// services that are registered to the gRPC server. The documents are built by
+// Build, or on the first request, so services should be registered before that.
+func NewHandler(server *grpc.Server, opts ...Option) *Handler {
+	options := &options{
+		prefix:  "/openapi",
+		title:   "API",
+		version: "version not set",
+	}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	return &Handler{
+		server:  server,
+		options: options,
+	}
+}
+
+// Build builds and validates the OpenAPI documents, if they were not built before.
+// It returns the error of the first build.
+func (h *Handler) Build() error {
+	h.once.Do(func() {
+		h.v2, h.v3, h.err = h.build()
+	})
+	return h.err
+}
+
+func (h *Handler) build() ([]byte, []byte, error) {
+	if h.options.ui != NoUI && h.options.assetsURL == "" {
+		return nil, nil, fmt.Errorf("openapi: no assets URL for %s", h.options.ui)
+	}
+	g := newGenerator(h.options.protoNames)
+	serviceNames := make([]string, 0, len(h.server.GetServiceInfo()))
+	for name := range h.server.GetServiceInfo() {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	for _, name := range serviceNames {
+		desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			continue
+		}
+		if service, ok := desc.(protoreflect.ServiceDescriptor); ok {
+			g.addService(service)
+		}
+	}
+	doc := g.document()
+	for i, data := range h.options.documents {
+		var embedded object
+		if err := json.Unmarshal(data, &embedded); err != nil {
+			return nil, nil, fmt.Errorf("openapi: invalid document %d: %w", i, err)
+		}
+		if embedded["swagger"] != "2.0" {
+			return nil, nil, fmt.Errorf("openapi: document %d is not a Swagger 2.0 document", i)
+		}
+		merge(doc, embedded)
+	}
+	prune(doc)
+	doc["info"] = object{"title": h.options.title, "version": h.options.version}
+	if h.options.basePath != "" && h.options.basePath != "/" {
+		doc["basePath"] = h.options.basePath
+	}
+	v2, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Convert a copy, so that the conversion does not modify the Swagger 2.0 document.
+	var docCopy object
+	if err := json.Unmarshal(v2, &docCopy); err != nil {
+		return nil, nil, err
+	}
+	v3, err := json.Marshal(toV3(docCopy))
+	if err != nil {
+		return nil, nil, err
+	}
+	return v2, v3, nil
+}
+
+// merge merges the paths, definitions and tags of src into dst.
+func merge(dst, src object) {
+	paths := objectOf(dst["paths"])
+	for path, item := range objectOf(src["paths"]) {
+		dstItem, ok := paths[path].(object)
+		if !ok {
+			dstItem = make(object)
+			paths[path] = dstItem
+		}
+		for method, operation := range objectOf(item) {
+			dstItem[method] = operation
+		}
+	}
+	definitions := objectOf(dst["definitions"])
+	for name, definition := range objectOf(src["definitions"]) {
+		definitions[name] = definition
+	}
+	tags := listOf(dst["tags"])
+	names := make(map[interface{}]bool, len(tags))
+	for _, tag := range tags {
+		names[objectOf(tag)["name"]] = true
+	}
+	for _, tag := range listOf(src["tags"]) {
+		if name := objectOf(tag)["name"]; !names[name] {
+			names[name] = true
+			tags = append(tags, tag)
+		}
+	}
+	dst["tags"] = tags
+}
+
+// prune removes the definitions that are not referenced by operations,
+// such as generated definitions of operations that were replaced by embedded documents.
+func prune(doc object) {
+	definitions := objectOf(doc["definitions"])
+	referenced := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case object:
+			for k, value := range v {
+				ref, ok := value.(string)
+				if !ok || k != "$ref" {
+					walk(value)
+					continue
+				}
+				name := strings.TrimPrefix(ref, "#/definitions/")
+				if !referenced[name] {
+					referenced[name] = true
+					walk(definitions[name])
+				}
+			}
+		case []interface{}:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+	walk(doc["paths"])
+	for name := range definitions {
+		if !referenced[name] {
+			delete(definitions, name)
+		}
+	}
+}
+
+// ServeHTTP serves the documents on /v2.json and /v3.json, and the UI on /.
+// The prefix should be stripped from the request path.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	switch r.URL.Path {
+	case "/v2.json", "/v3.json":
+		if err := h.Build(); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2.json" {
+			w.Write(h.v2)
+		} else {
+			w.Write(h.v3)
+		}
+	default:
+		if h.options.ui != NoUI && h.options.ui.serveHTTP(w, r, h.options.title, h.options.assetsURL) {
+			return
+		}
+		http.NotFound(w, r)
+	}
+}
+
+// Register builds the documents (see Build) and registers the handler to the HTTP
+// server of a backbone server. Services must be registered to the gRPC server before.
+func (h *Handler) Register(s *server.Server) error {
+	if err := h.Build(); err != nil {
+		return err
+	}
+	s.HTTP.ServeMux.Handle(h.options.prefix+"/", http.StripPrefix(h.options.prefix, h))
+	return nil
+}
+
+// Register registers a new handler that serves the OpenAPI documents for the
+// gRPC-gateway of the backbone server to its HTTP server. Services must be
+// registered to the gRPC server before.
+func Register(s *server.Server, opts ...Option) error {
+	return NewHandler(s.GRPC.Server, opts...).Register(s)
+}
@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	featureflag "htdvisser.dev/exp/backbone/api/featureflag/v1alpha1"
+)
+
+func get(t *testing.T, h http.Handler, path string) (*httptest.ResponseRecorder, object) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var doc object
+	if strings.HasSuffix(path, ".json") {
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("invalid JSON in %s: %v", path, err)
+		}
+	}
+	return rec, doc
+}
+
+func TestHandler(t *testing.T) {
+	server := grpc.NewServer()
+	featureflag.RegisterFeatureFlagServiceServer(server, featureflag.UnimplementedFeatureFlagServiceServer{})
+
+	h := NewHandler(server,
+		WithBasePath("/api"),
+		WithInfo("Test API", "v1"),
+		WithUI(SwaggerUI, "https://assets.example.com/"),
+		WithDocument([]byte(`{
+			"swagger": "2.0",
+			"paths": {"/featureflag/v1alpha1/flags": {"get": {"operationId": "Embedded", "responses": {}}}},
+			"tags": [{"name": "Embedded"}]
+		}`)),
+	)
+
+	_, v2 := get(t, h, "/v2.json")
+	if v2["basePath"] != "/api" {
+		t.Errorf("basePath is %v, want /api", v2["basePath"])
+	}
+	paths := objectOf(v2["paths"])
+	override := objectOf(objectOf(paths["/featureflag/v1alpha1/flags/{name}/override"])["post"])
+	if override["operationId"] != "FeatureFlagService_SetOverride" {
+		t.Errorf("unexpected override operation %v", override)
+	}
+	if list := objectOf(objectOf(paths["/featureflag/v1alpha1/flags"])["get"]); list["operationId"] != "Embedded" {
+		t.Errorf("embedded operation did not replace generated operation: %v", list)
+	}
+	definitions := objectOf(v2["definitions"])
+	if _, ok := definitions["htdvisser.backbone.featureflag.v1alpha1.SetOverrideRequest"]; !ok {
+		t.Error("SetOverrideRequest is not defined")
+	}
+	if _, ok := definitions["htdvisser.backbone.featureflag.v1alpha1.ListFlagsResponse"]; ok {
+		t.Error("ListFlagsResponse is defined, but not referenced")
+	}
+	if tags := listOf(v2["tags"]); len(tags) != 2 {
+		t.Errorf("tags are %v, want FeatureFlagService and Embedded", tags)
+	}
+
+	_, v3 := get(t, h, "/v3.json")
+	if v3["openapi"] != "3.0.3" {
+		t.Errorf("openapi version is %v", v3["openapi"])
+	}
+	override = objectOf(objectOf(objectOf(v3["paths"])["/featureflag/v1alpha1/flags/{name}/override"])["post"])
+	schema := objectOf(objectOf(objectOf(objectOf(override["requestBody"])["content"])["application/json"])["schema"])
+	if schema["$ref"] != "#/components/schemas/htdvisser.backbone.featureflag.v1alpha1.SetOverrideRequest" {
+		t.Errorf("unexpected request body schema %v", schema)
+	}
+	for _, parameter := range listOf(override["parameters"]) {
+		if parameter := objectOf(parameter); parameter["name"] != "name" || objectOf(parameter["schema"])["type"] != "string" {
+			t.Errorf("unexpected parameter %v", parameter)
+		}
+	}
+
+	rec, _ := get(t, h, "/")
+	if !strings.Contains(rec.Body.String(), "https://assets.example.com/swagger-ui-dist@5/swagger-ui-bundle.js") || !strings.Contains(rec.Header().Get("Content-Security-Policy"), "script-src 'self' https://assets.example.com;") {
+		t.Errorf("unexpected UI response %v %s", rec.Header(), rec.Body)
+	}
+	if rec, _ := get(t, h, "/unknown"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown path returned %d", rec.Code)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	server := grpc.NewServer()
+	if err := NewHandler(server, WithUI(Redoc, "")).Build(); err == nil {
+		t.Error("expected error for UI without assets URL")
+	}
+	h := NewHandler(server, WithDocument([]byte(`{"openapi": "3.0.3"}`)))
+	if err := h.Build(); err == nil {
+		t.Error("expected error for document that is not a Swagger 2.0 document")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2.json", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("invalid documents returned %d", rec.Code)
+	}
+}
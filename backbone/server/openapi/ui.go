@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// UI is a UI for exploring the API.
+type UI int
+
+const (
+	// NoUI does not serve a UI.
+	NoUI UI = iota
+	// SwaggerUI serves Swagger UI.
+	SwaggerUI
+	// Redoc serves Redoc.
+	Redoc
+)
+
+var uiTemplate = template.Must(template.New("ui").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+{{- if eq .UI "swagger-ui" }}
+<link rel="stylesheet" href="{{ .AssetsURL }}/swagger-ui-dist@5/swagger-ui.css">
+{{- end }}
+</head>
+<body>
+{{- if eq .UI "swagger-ui" }}
+<div id="swagger-ui"></div>
+<script src="{{ .AssetsURL }}/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script src="swagger-ui.js"></script>
+{{- else }}
+<redoc spec-url="v3.json"></redoc>
+<script src="{{ .AssetsURL }}/redoc@2/bundles/redoc.standalone.js"></script>
+{{- end }}
+</body>
+</html>
+`))
+
+// swaggerUIScript is served as a separate file, so that the Content-Security-Policy
+// does not need to allow inline scripts.
+const swaggerUIScript = `SwaggerUIBundle({url: "v3.json", dom_id: "#swagger-ui", deepLinking: true});
+`
+
+func (ui UI) String() string {
+	switch ui {
+	case SwaggerUI:
+		return "swagger-ui"
+	case Redoc:
+		return "redoc"
+	default:
+		return ""
+	}
+}
+
+func (ui UI) contentSecurityPolicy(assetsURL string) string {
+	return "default-src 'self'; " +
+		"script-src 'self' " + assetsURL + "; " +
+		"style-src 'self' 'unsafe-inline' " + assetsURL + " https://fonts.googleapis.com; " +
+		"font-src 'self' https://fonts.gstatic.com; " +
+		"img-src 'self' data: " + assetsURL + "; " +
+		"worker-src 'self' blob:; " +
+		"frame-ancestors 'none'"
+}
+
+func (ui UI) serveHTTP(w http.ResponseWriter, r *http.Request, title, assetsURL string) bool {
+	switch {
+	case r.URL.Path == "/" || r.URL.Path == "":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", ui.contentSecurityPolicy(assetsURL))
+		uiTemplate.Execute(w, struct {
+			Title     string
+			UI        string
+			AssetsURL string
+		}{
+			Title:     title,
+			UI:        ui.String(),
+			AssetsURL: assetsURL,
+		})
+		return true
+	case r.URL.Path == "/swagger-ui.js" && ui == SwaggerUI:
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Write([]byte(swaggerUIScript))
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,154 @@
+package openapi
+
+import "strings"
+
+// toV3 converts a Swagger 2.0 document to an OpenAPI 3.0 document.
+// It modifies the given document, so callers should pass a copy.
+func toV3(doc object) object {
+	consumes := stringsOf(doc["consumes"], "application/json")
+	produces := stringsOf(doc["produces"], "application/json")
+	v3 := object{
+		"openapi": "3.0.3",
+		"info":    doc["info"],
+		"paths":   object{},
+		"components": object{
+			"schemas": doc["definitions"],
+		},
+	}
+	if tags, ok := doc["tags"]; ok {
+		v3["tags"] = tags
+	}
+	if basePath, ok := doc["basePath"].(string); ok && basePath != "" {
+		v3["servers"] = []interface{}{object{"url": basePath}}
+	}
+	paths := v3["paths"].(object)
+	for path, item := range objectOf(doc["paths"]) {
+		v3Item := make(object)
+		for method, operation := range objectOf(item) {
+			if operation, ok := operation.(object); ok {
+				v3Item[method] = toV3Operation(operation, consumes, produces)
+			}
+		}
+		paths[path] = v3Item
+	}
+	rewriteRefs(v3)
+	return v3
+}
+
+func toV3Operation(operation object, consumes, produces []string) object {
+	if c, ok := operation["consumes"]; ok {
+		consumes = stringsOf(c)
+	}
+	if p, ok := operation["produces"]; ok {
+		produces = stringsOf(p)
+	}
+	v3 := make(object)
+	for k, v := range operation {
+		switch k {
+		case "parameters", "responses", "consumes", "produces", "schemes":
+		default:
+			v3[k] = v
+		}
+	}
+	var parameters []interface{}
+	for _, parameter := range listOf(operation["parameters"]) {
+		parameter := objectOf(parameter)
+		switch parameter["in"] {
+		case "body":
+			requestBody := object{
+				"required": parameter["required"] == true,
+				"content":  content(parameter["schema"], consumes),
+			}
+			if description, ok := parameter["description"]; ok {
+				requestBody["description"] = description
+			}
+			v3["requestBody"] = requestBody
+		case "formData":
+			// Not produced by the gRPC-gateway.
+		default:
+			v3Parameter := make(object)
+			schema := make(object)
+			for k, v := range parameter {
+				switch k {
+				case "name", "in", "required", "description":
+					v3Parameter[k] = v
+				case "collectionFormat", "allowEmptyValue":
+				case "schema":
+					for k, v := range objectOf(v) {
+						schema[k] = v
+					}
+				default:
+					schema[k] = v
+				}
+			}
+			v3Parameter["schema"] = schema
+			parameters = append(parameters, v3Parameter)
+		}
+	}
+	if len(parameters) > 0 {
+		v3["parameters"] = parameters
+	}
+	responses := make(object)
+	for code, response := range objectOf(operation["responses"]) {
+		response := objectOf(response)
+		v3Response := object{"description": response["description"]}
+		if v3Response["description"] == nil {
+			v3Response["description"] = ""
+		}
+		if schema, ok := response["schema"]; ok {
+			v3Response["content"] = content(schema, produces)
+		}
+		responses[code] = v3Response
+	}
+	v3["responses"] = responses
+	return v3
+}
+
+func content(schema interface{}, mediaTypes []string) object {
+	content := make(object, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = object{"schema": schema}
+	}
+	return content
+}
+
+// rewriteRefs rewrites references to Swagger 2.0 definitions to OpenAPI 3.0 components.
+func rewriteRefs(v interface{}) {
+	switch v := v.(type) {
+	case object:
+		for k, value := range v {
+			if ref, ok := value.(string); ok && k == "$ref" {
+				v[k] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			rewriteRefs(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			rewriteRefs(value)
+		}
+	}
+}
+
+func objectOf(v interface{}) object {
+	o, _ := v.(object)
+	return o
+}
+
+func listOf(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func stringsOf(v interface{}, defaults ...string) []string {
+	var out []string
+	for _, v := range listOf(v) {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return defaults
+	}
+	return out
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"net"
+
 	"htdvisser.dev/exp/backbone/server/grpc"
 	"htdvisser.dev/exp/backbone/server/http"
 )
@@ -10,6 +12,9 @@ type options struct {
 	GRPCOptions         []grpc.Option
 	InternalHTTPOptions []http.Option
 	InternalGRPCOptions []grpc.Option
+
+	Listen       func(name, network, address string) (net.Listener, error)
+	ListenPacket func(name, network, address string) (net.PacketConn, error)
 }
 
 func (o *options) apply(opts ...Option) {
@@ -56,3 +61,23 @@ func WithInternalGRPCOptions(opts ...grpc.Option) Option {
 		o.InternalGRPCOptions = append(o.InternalGRPCOptions, opts...)
 	})
 }
+
+// WithListenFunc returns an Option that replaces the function that creates the
+// listeners for the registered TCP servers. The function is called with the name
+// of the registered server. If it returns a nil listener and no error, the server
+// is not served.
+func WithListenFunc(listen func(name, network, address string) (net.Listener, error)) Option {
+	return option(func(o *options) {
+		o.Listen = listen
+	})
+}
+
+// WithListenPacketFunc returns an Option that replaces the function that creates the
+// packet connections for the registered UDP servers. The function is called with the
+// name of the registered server. If it returns a nil connection and no error, the
+// server is not served.
+func WithListenPacketFunc(listenPacket func(name, network, address string) (net.PacketConn, error)) Option {
+	return option(func(o *options) {
+		o.ListenPacket = listenPacket
+	})
+}
@@ -0,0 +1,334 @@
+// Package quota attributes gRPC calls to tenants, accounts their usage per
+// method, and enforces daily and monthly quotas.
+//
+// The tenant of a call is the tenant in the context (see NewContextWithTenant),
+// the principal of the featureflag package, or the value of the tenant metadata
+// key (if configured), in that order. Calls that can not be attributed to a tenant
+// are not accounted and not limited.
+//
+// Quotas are checked when a call starts. A call of a tenant that has reached
+// its daily or monthly quota fails with ResourceExhausted. The usage of a call
+// (one request and the size of its messages) is accounted when the call ends,
+// so streams that are already running are not interrupted.
+//
+// Quotas are soft limits. Calls that start at the same time are all checked
+// against the usage before any of them is accounted, so a tenant can exceed a
+// quota by the calls that are in progress when the quota is reached.
+package quota
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"htdvisser.dev/exp/backbone/server"
+	"htdvisser.dev/exp/backbone/server/featureflag"
+)
+
+// Limits are the quotas of a tenant. Zero values are unlimited.
+type Limits struct {
+	DailyRequests   int64 `json:"daily_requests,omitempty" yaml:"daily_requests,omitempty"`
+	MonthlyRequests int64 `json:"monthly_requests,omitempty" yaml:"monthly_requests,omitempty"`
+	// DailyBytes is the limit of the total of received and sent bytes in a day.
+	DailyBytes int64 `json:"daily_bytes,omitempty" yaml:"daily_bytes,omitempty"`
+	// MonthlyBytes is the limit of the total of received and sent bytes in a month.
+	MonthlyBytes int64 `json:"monthly_bytes,omitempty" yaml:"monthly_bytes,omitempty"`
+}
+
+// Period returns the request and byte limits in the period.
+func (l Limits) Period(period Period) (requests, bytes int64) {
+	if period == Month {
+		return l.MonthlyRequests, l.MonthlyBytes
+	}
+	return l.DailyRequests, l.DailyBytes
+}
+
+// Config is the configuration for quotas.
+type Config struct {
+	// TenantMetadata is the metadata key that contains the tenant.
+	// If empty (the default), tenants are not taken from metadata.
+	//
+	// The metadata is set by the client, so this must only be used if all clients
+	// are trusted to set it, such as a gateway or proxy in front of the server that
+	// authenticates callers and sets (or removes) the key. Otherwise callers can
+	// choose the tenant that their calls are attributed to, or omit it to avoid quotas.
+	TenantMetadata string
+	// Limits are the default limits of tenants.
+	Limits Limits
+}
+
+// DefaultConfig returns the default config for quotas.
+func DefaultConfig() *Config {
+	return &Config{}
+}
+
+// Flags returns a flagset that can be added to the command line.
+func (c *Config) Flags(prefix string, defaults *Config) *pflag.FlagSet {
+	var flags pflag.FlagSet
+	if defaults == nil {
+		defaults = DefaultConfig()
+	}
+	flags.StringVar(&c.TenantMetadata, prefix+"quota.tenant-metadata", defaults.TenantMetadata, "Metadata key that contains the tenant of a call (only for trusted clients, disabled if empty)")
+	flags.Int64Var(&c.Limits.DailyRequests, prefix+"quota.daily-requests", defaults.Limits.DailyRequests, "Number of calls per tenant per day (0 is unlimited)")
+	flags.Int64Var(&c.Limits.MonthlyRequests, prefix+"quota.monthly-requests", defaults.Limits.MonthlyRequests, "Number of calls per tenant per month (0 is unlimited)")
+	flags.Int64Var(&c.Limits.DailyBytes, prefix+"quota.daily-bytes", defaults.Limits.DailyBytes, "Message bytes per tenant per day (0 is unlimited)")
+	flags.Int64Var(&c.Limits.MonthlyBytes, prefix+"quota.monthly-bytes", defaults.Limits.MonthlyBytes, "Message bytes per tenant per month (0 is unlimited)")
+	return &flags
+}
+
+type options struct {
+	clock  clock.Clock
+	store  Store
+	limits func(ctx context.Context, tenant string) Limits
+}
+
+func newOptions(opts ...Option) *options {
+	options := &options{
+		clock: clock.New(),
+	}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	if options.store == nil {
+		options.store = NewMemoryStore()
+	}
+	return options
+}
+
+// Option is an option for Quotas.
+type Option interface {
+	apply(*options)
+}
+
+type option func(*options)
+
+func (f option) apply(opts *options) {
+	f(opts)
+}
+
+// WithClock returns an option that sets the clock.
+func WithClock(clock clock.Clock) Option {
+	return option(func(o *options) {
+		o.clock = clock
+	})
+}
+
+// WithStore returns an option that sets the store for usage counters.
+// The default is a MemoryStore.
+func WithStore(store Store) Option {
+	return option(func(o *options) {
+		o.store = store
+	})
+}
+
+// WithLimits returns an option that sets a function that returns the limits
+// of a tenant, such as limits that depend on the plan of the tenant. The
+// default is to use the limits of the config for all tenants.
+func WithLimits(limits func(ctx context.Context, tenant string) Limits) Option {
+	return option(func(o *options) {
+		o.limits = limits
+	})
+}
+
+type tenantKeyType struct{}
+
+var tenantKey tenantKeyType
+
+// NewContextWithTenant returns a context derived from parent that contains the tenant
+// that calls are attributed to.
+func NewContextWithTenant(parent context.Context, tenant string) context.Context {
+	return context.WithValue(parent, tenantKey, tenant)
+}
+
+// Quotas accounts the usage of tenants and enforces their quotas.
+type Quotas struct {
+	clock          clock.Clock
+	store          Store
+	tenantMetadata string
+	limits         func(ctx context.Context, tenant string) Limits
+}
+
+// New returns new Quotas for the config.
+func New(config Config, opts ...Option) *Quotas {
+	options := newOptions(opts...)
+	q := &Quotas{
+		clock:          options.clock,
+		store:          options.store,
+		tenantMetadata: config.TenantMetadata,
+		limits:         options.limits,
+	}
+	if q.limits == nil {
+		q.limits = func(context.Context, string) Limits { return config.Limits }
+	}
+	return q
+}
+
+// Tenant returns the tenant that a call with the given context is attributed to.
+func (q *Quotas) Tenant(ctx context.Context) string {
+	if tenant, _ := ctx.Value(tenantKey).(string); tenant != "" {
+		return tenant
+	}
+	if principal := featureflag.PrincipalFromContext(ctx); principal != "" {
+		return principal
+	}
+	if q.tenantMetadata != "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(q.tenantMetadata); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// Limits returns the limits of the tenant.
+func (q *Quotas) Limits(ctx context.Context, tenant string) Limits {
+	return q.limits(ctx, tenant)
+}
+
+// Usage returns the usage of the tenant per gRPC method in the period that contains t.
+func (q *Quotas) Usage(ctx context.Context, tenant string, period Period, t time.Time) (map[string]Usage, error) {
+	return q.store.Get(ctx, tenant, period, t)
+}
+
+// Tenants returns the tenants that have usage in the period that contains t.
+func (q *Quotas) Tenants(ctx context.Context, period Period, t time.Time) ([]string, error) {
+	return q.store.Tenants(ctx, period, t)
+}
+
+// check returns a ResourceExhausted error if the tenant has reached a quota.
+// Errors of the store are logged, and do not block calls. Usage is not reserved,
+// so concurrent calls may all pass the check (see the package documentation).
+func (q *Quotas) check(ctx context.Context, tenant string, now time.Time) error {
+	limits := q.limits(ctx, tenant)
+	for _, period := range []Period{Day, Month} {
+		requestLimit, byteLimit := limits.Period(period)
+		if requestLimit <= 0 && byteLimit <= 0 {
+			continue
+		}
+		methods, err := q.store.Get(ctx, tenant, period, now)
+		if err != nil {
+			log.Printf("Failed to get usage of tenant %q: %v", tenant, err)
+			return nil
+		}
+		total := Total(methods)
+		if requestLimit > 0 && total.Requests >= requestLimit {
+			return status.Errorf(codes.ResourceExhausted, "%s request quota of %d exceeded", period, requestLimit)
+		}
+		if byteLimit > 0 && total.Bytes() >= byteLimit {
+			return status.Errorf(codes.ResourceExhausted, "%s byte quota of %d exceeded", period, byteLimit)
+		}
+	}
+	return nil
+}
+
+// accountTimeout is the timeout for accounting the usage of a call. The usage
+// is accounted after the context of the call is done, so it uses its own context.
+const accountTimeout = 5 * time.Second
+
+func (q *Quotas) account(tenant, method string, start time.Time, usage Usage) {
+	ctx, cancel := context.WithTimeout(context.Background(), accountTimeout)
+	defer cancel()
+	if err := q.store.Add(ctx, tenant, method, start, usage); err != nil {
+		log.Printf("Failed to account usage of tenant %q: %v", tenant, err)
+	}
+}
+
+func messageSize(msg interface{}) int64 {
+	if msg, ok := msg.(proto.Message); ok {
+		return int64(proto.Size(msg))
+	}
+	return 0
+}
+
+// UnaryServerInterceptor returns a unary server interceptor that enforces quotas
+// and accounts usage.
+func (q *Quotas) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		tenant := q.Tenant(ctx)
+		if tenant == "" {
+			return handler(ctx, req)
+		}
+		start := q.clock.Now()
+		if err := q.check(ctx, tenant, start); err != nil {
+			return nil, err
+		}
+		res, err := handler(ctx, req)
+		usage := Usage{Requests: 1, ReceivedBytes: messageSize(req)}
+		if err == nil {
+			usage.SentBytes = messageSize(res)
+		}
+		q.account(tenant, info.FullMethod, start, usage)
+		return res, err
+	}
+}
+
+// serverStream counts the sizes of the messages of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	receivedBytes, sentBytes int64
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.receivedBytes, messageSize(m))
+	}
+	return err
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.sentBytes, messageSize(m))
+	}
+	return err
+}
+
+// StreamServerInterceptor returns a stream server interceptor that enforces quotas
+// and accounts usage.
+func (q *Quotas) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tenant := q.Tenant(ss.Context())
+		if tenant == "" {
+			return handler(srv, ss)
+		}
+		start := q.clock.Now()
+		if err := q.check(ss.Context(), tenant, start); err != nil {
+			return err
+		}
+		stream := &serverStream{ServerStream: ss}
+		err := handler(srv, stream)
+		q.account(tenant, info.FullMethod, start, Usage{
+			Requests:      1,
+			ReceivedBytes: atomic.LoadInt64(&stream.receivedBytes),
+			SentBytes:     atomic.LoadInt64(&stream.sentBytes),
+		})
+		return err
+	}
+}
+
+// Register adds the interceptors of the quotas to the gRPC server, and registers
+// the quota service to the internal gRPC server.
+func (q *Quotas) Register(ctx context.Context, s *server.Server) error {
+	s.GRPC.AddUnaryInterceptor(q.UnaryServerInterceptor())
+	s.GRPC.AddStreamInterceptor(q.StreamServerInterceptor())
+	return NewService(q).Register(ctx, s)
+}
+
+// Register enforces quotas on the gRPC server, and registers the quota service
+// to the internal gRPC server.
+func Register(ctx context.Context, s *server.Server, config Config, opts ...Option) (*Quotas, error) {
+	q := New(config, opts...)
+	if err := q.Register(ctx, s); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
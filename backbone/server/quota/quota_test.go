@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	quota "htdvisser.dev/exp/backbone/api/quota/v1alpha1"
+	"htdvisser.dev/exp/backbone/backbonetest"
+	"htdvisser.dev/exp/backbone/server/featureflag"
+)
+
+func TestQuotas(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2023, time.December, 6, 18, 0, 0, 0, time.UTC))
+
+	config := DefaultConfig()
+	config.TenantMetadata = "x-tenant-id"
+	config.Limits = Limits{DailyRequests: 2, MonthlyRequests: 3}
+
+	h := backbonetest.New(t)
+	if _, err := Register(context.Background(), h.Server, *config, WithClock(clock)); err != nil {
+		t.Fatal(err)
+	}
+	h.Start()
+
+	health := healthpb.NewHealthClient(h.GRPCConn())
+	alice := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "alice")
+	check := func(ctx context.Context) codes.Code {
+		_, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+		return status.Code(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := check(alice); code != codes.OK {
+			t.Fatalf("call %d failed with %s", i+1, code)
+		}
+	}
+	if code := check(alice); code != codes.ResourceExhausted {
+		t.Errorf("call over daily quota returned %s, want ResourceExhausted", code)
+	}
+	if code := check(context.Background()); code != codes.OK {
+		t.Errorf("call without tenant returned %s, want OK", code)
+	}
+
+	clock.Add(24 * time.Hour)
+	if code := check(alice); code != codes.OK {
+		t.Errorf("call on next day returned %s, want OK", code)
+	}
+	if code := check(alice); code != codes.ResourceExhausted {
+		t.Errorf("call over monthly quota returned %s, want ResourceExhausted", code)
+	}
+
+	client := quota.NewQuotaServiceClient(h.InternalGRPCConn())
+	usage, err := client.GetUsage(context.Background(), &quota.GetUsageRequest{
+		Tenant: "alice",
+		Period: quota.Period_MONTH,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := usage.GetTotal().GetRequests(); got != 3 {
+		t.Errorf("monthly usage is %d requests, want 3", got)
+	}
+	if got := usage.GetLimits().GetRequests(); got != 3 {
+		t.Errorf("monthly limit is %d requests, want 3", got)
+	}
+	if got := usage.GetStart().AsTime(); !got.Equal(time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("period starts at %s", got)
+	}
+	if methods := usage.GetMethods(); len(methods) != 1 || methods[0].GetMethod() != "/grpc.health.v1.Health/Check" {
+		t.Errorf("unexpected method usage: %v", methods)
+	}
+
+	list, err := client.ListUsage(context.Background(), &quota.ListUsageRequest{Period: quota.Period_DAY})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenants := list.GetTenants(); len(tenants) != 1 || tenants[0].GetTenant() != "alice" || tenants[0].GetTotal().GetRequests() != 1 {
+		t.Errorf("unexpected daily usage: %v", tenants)
+	}
+}
+
+func TestTenant(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "from-metadata"))
+	if tenant := New(*DefaultConfig()).Tenant(ctx); tenant != "" {
+		t.Errorf("tenant is %q, want no tenant from metadata by default", tenant)
+	}
+	q := New(Config{TenantMetadata: "x-tenant-id"})
+	if tenant := q.Tenant(ctx); tenant != "from-metadata" {
+		t.Errorf("tenant is %q, want from-metadata", tenant)
+	}
+	ctx = featureflag.NewContextWithPrincipal(ctx, "from-principal")
+	if tenant := q.Tenant(ctx); tenant != "from-principal" {
+		t.Errorf("tenant is %q, want from-principal", tenant)
+	}
+	if tenant := q.Tenant(NewContextWithTenant(ctx, "from-context")); tenant != "from-context" {
+		t.Errorf("tenant is %q, want from-context", tenant)
+	}
+}
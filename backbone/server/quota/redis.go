@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisStore is a Store that stores usage counters in Redis.
+//
+// The usage of each tenant in each period is stored in a hash under
+// "<prefix>usage:<period>:<tenant>", where period is a day ("2023-12-06") or a
+// month ("2023-12"). The fields of the hash are "<method>:<counter>". The tenants
+// with usage in each period are kept in a set under "<prefix>tenants:<period>".
+// Daily keys expire after DailyRetention, monthly keys after MonthlyRetention.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore returns a new Store on top of the given Redis client,
+// which is typically obtained from a redisconfig.Config.
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisStore) usageKey(tenant string, period Period, t time.Time) string {
+	return s.prefix + "usage:" + period.key(t) + ":" + tenant
+}
+
+func (s *RedisStore) tenantsKey(period Period, t time.Time) string {
+	return s.prefix + "tenants:" + period.key(t)
+}
+
+// Counters in the usage hashes.
+const (
+	requestsCounter      = "requests"
+	receivedBytesCounter = "received_bytes"
+	sentBytesCounter     = "sent_bytes"
+)
+
+// Add implements Store.
+func (s *RedisStore) Add(ctx context.Context, tenant, method string, t time.Time, usage Usage) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, period := range []Period{Day, Month} {
+			usageKey, tenantsKey := s.usageKey(tenant, period, t), s.tenantsKey(period, t)
+			pipe.HIncrBy(ctx, usageKey, method+":"+requestsCounter, usage.Requests)
+			pipe.HIncrBy(ctx, usageKey, method+":"+receivedBytesCounter, usage.ReceivedBytes)
+			pipe.HIncrBy(ctx, usageKey, method+":"+sentBytesCounter, usage.SentBytes)
+			pipe.Expire(ctx, usageKey, retention(period))
+			pipe.SAdd(ctx, tenantsKey, tenant)
+			pipe.Expire(ctx, tenantsKey, retention(period))
+		}
+		return nil
+	})
+	return err
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(ctx context.Context, tenant string, period Period, t time.Time) (map[string]Usage, error) {
+	fields, err := s.client.HGetAll(ctx, s.usageKey(tenant, period, t)).Result()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]Usage)
+	for field, value := range fields {
+		sep := strings.LastIndexByte(field, ':')
+		if sep < 0 {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		method, counter := field[:sep], field[sep+1:]
+		usage := out[method]
+		switch counter {
+		case requestsCounter:
+			usage.Requests = n
+		case receivedBytesCounter:
+			usage.ReceivedBytes = n
+		case sentBytesCounter:
+			usage.SentBytes = n
+		}
+		out[method] = usage
+	}
+	return out, nil
+}
+
+// Tenants implements Store.
+func (s *RedisStore) Tenants(ctx context.Context, period Period, t time.Time) ([]string, error) {
+	tenants, err := s.client.SMembers(ctx, s.tenantsKey(period, t)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
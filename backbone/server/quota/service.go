@@ -0,0 +1,121 @@
+package quota
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	quota "htdvisser.dev/exp/backbone/api/quota/v1alpha1"
+	"htdvisser.dev/exp/backbone/server"
+)
+
+// Service is the quota service that is used to inspect the usage of tenants.
+type Service struct {
+	quotas *Quotas
+
+	quota.UnimplementedQuotaServiceServer
+}
+
+// NewService returns a new quota service for the quotas.
+func NewService(quotas *Quotas) *Service {
+	return &Service{quotas: quotas}
+}
+
+// Register registers the quota service to the internal gRPC server,
+// and its gateway routes to the internal HTTP server.
+func (svc *Service) Register(ctx context.Context, s *server.Server) error {
+	quota.RegisterQuotaServiceServer(s.InternalGRPC, svc)
+	if err := quota.RegisterQuotaServiceHandlerClient(ctx, s.InternalGRPC.Gateway, quota.NewQuotaServiceClient(s.InternalGRPC.InProcessConn())); err != nil {
+		return err
+	}
+	s.InternalHTTP.ServeMux.Handle("/quota/", s.InternalGRPC.Gateway)
+	return nil
+}
+
+func toProtoUsage(usage Usage) *quota.Usage {
+	return &quota.Usage{
+		Requests:      usage.Requests,
+		ReceivedBytes: usage.ReceivedBytes,
+		SentBytes:     usage.SentBytes,
+	}
+}
+
+// timeOrNow returns the time of t, or the current time if t is nil.
+func (svc *Service) timeOrNow(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return svc.quotas.clock.Now()
+	}
+	return t.AsTime()
+}
+
+func (svc *Service) tenantUsage(ctx context.Context, tenant string, period Period, at time.Time) (*quota.TenantUsage, error) {
+	methods, err := svc.quotas.Usage(ctx, tenant, period, at)
+	if err != nil {
+		return nil, err
+	}
+	requests, bytes := svc.quotas.Limits(ctx, tenant).Period(period)
+	res := &quota.TenantUsage{
+		Tenant: tenant,
+		Period: quota.Period(period),
+		Start:  timestamppb.New(period.Start(at)),
+		Total:  toProtoUsage(Total(methods)),
+		Limits: &quota.Limits{
+			Requests: requests,
+			Bytes:    bytes,
+		},
+	}
+	for method, usage := range methods {
+		res.Methods = append(res.Methods, &quota.MethodUsage{
+			Method: method,
+			Usage:  toProtoUsage(usage),
+		})
+	}
+	sort.Slice(res.Methods, func(i, j int) bool {
+		return res.Methods[i].Method < res.Methods[j].Method
+	})
+	return res, nil
+}
+
+func validatePeriod(period quota.Period) error {
+	switch period {
+	case quota.Period_DAY, quota.Period_MONTH:
+		return nil
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid period %s", period)
+	}
+}
+
+// GetUsage implements the QuotaService interface.
+func (svc *Service) GetUsage(ctx context.Context, req *quota.GetUsageRequest) (*quota.TenantUsage, error) {
+	if req.GetTenant() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing tenant")
+	}
+	if err := validatePeriod(req.GetPeriod()); err != nil {
+		return nil, err
+	}
+	return svc.tenantUsage(ctx, req.GetTenant(), Period(req.GetPeriod()), svc.timeOrNow(req.GetTime()))
+}
+
+// ListUsage implements the QuotaService interface.
+func (svc *Service) ListUsage(ctx context.Context, req *quota.ListUsageRequest) (*quota.ListUsageResponse, error) {
+	if err := validatePeriod(req.GetPeriod()); err != nil {
+		return nil, err
+	}
+	at := svc.timeOrNow(req.GetTime())
+	tenants, err := svc.quotas.Tenants(ctx, Period(req.GetPeriod()), at)
+	if err != nil {
+		return nil, err
+	}
+	var res quota.ListUsageResponse
+	for _, tenant := range tenants {
+		usage, err := svc.tenantUsage(ctx, tenant, Period(req.GetPeriod()), at)
+		if err != nil {
+			return nil, err
+		}
+		res.Tenants = append(res.Tenants, usage)
+	}
+	return &res, nil
+}
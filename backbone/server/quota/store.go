@@ -0,0 +1,189 @@
+package quota
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Period is the period in which usage is accounted.
+type Period int
+
+// Periods.
+const (
+	Day Period = iota
+	Month
+)
+
+// String implements fmt.Stringer.
+func (p Period) String() string {
+	switch p {
+	case Day:
+		return "daily"
+	case Month:
+		return "monthly"
+	default:
+		return "unknown"
+	}
+}
+
+// Start returns the start (in UTC) of the period that contains t.
+func (p Period) Start(t time.Time) time.Time {
+	t = t.UTC()
+	switch p {
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Retention of usage counters in stores.
+const (
+	DailyRetention   = 100 * 24 * time.Hour
+	MonthlyRetention = 2 * 366 * 24 * time.Hour
+)
+
+func retention(period Period) time.Duration {
+	if period == Month {
+		return MonthlyRetention
+	}
+	return DailyRetention
+}
+
+// key returns the key of the period that contains t, such as "2023-12-06"
+// for a day or "2023-12" for a month.
+func (p Period) key(t time.Time) string {
+	switch p {
+	case Month:
+		return p.Start(t).Format("2006-01")
+	default:
+		return p.Start(t).Format("2006-01-02")
+	}
+}
+
+// Usage is the usage of a tenant.
+type Usage struct {
+	// Requests is the number of calls.
+	Requests int64 `json:"requests"`
+	// ReceivedBytes is the size of the messages received from the tenant.
+	ReceivedBytes int64 `json:"received_bytes"`
+	// SentBytes is the size of the messages sent to the tenant.
+	SentBytes int64 `json:"sent_bytes"`
+}
+
+// Add adds other to the usage.
+func (u *Usage) Add(other Usage) {
+	u.Requests += other.Requests
+	u.ReceivedBytes += other.ReceivedBytes
+	u.SentBytes += other.SentBytes
+}
+
+// Bytes returns the total of received and sent bytes.
+func (u Usage) Bytes() int64 {
+	return u.ReceivedBytes + u.SentBytes
+}
+
+// Total returns the total usage of all methods.
+func Total(methods map[string]Usage) Usage {
+	var total Usage
+	for _, usage := range methods {
+		total.Add(usage)
+	}
+	return total
+}
+
+// Store stores usage counters.
+type Store interface {
+	// Add adds the usage of a tenant for a gRPC method to the day and the month that contain t.
+	Add(ctx context.Context, tenant, method string, t time.Time, usage Usage) error
+	// Get returns the usage of a tenant per gRPC method in the period that contains t.
+	Get(ctx context.Context, tenant string, period Period, t time.Time) (map[string]Usage, error)
+	// Tenants returns the tenants that have usage in the period that contains t.
+	Tenants(ctx context.Context, period Period, t time.Time) ([]string, error)
+}
+
+// MemoryStore is an in-memory Store. It is useful for development and testing,
+// but usage is lost when the process exits and is not shared between processes.
+// Like the RedisStore, it drops the usage of days after DailyRetention and the
+// usage of months after MonthlyRetention.
+type MemoryStore struct {
+	mu sync.Mutex
+	// usage is indexed by period, tenant and method.
+	usage map[periodKey]map[string]map[string]Usage
+}
+
+type periodKey struct {
+	period Period
+	start  time.Time
+}
+
+func newPeriodKey(period Period, t time.Time) periodKey {
+	return periodKey{period: period, start: period.Start(t)}
+}
+
+// NewMemoryStore returns a new in-memory Store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		usage: make(map[periodKey]map[string]map[string]Usage),
+	}
+}
+
+// prune removes the usage of periods that are past their retention at t.
+func (s *MemoryStore) prune(t time.Time) {
+	for key := range s.usage {
+		if t.Sub(key.start) > retention(key.period) {
+			delete(s.usage, key)
+		}
+	}
+}
+
+// Add implements Store.
+func (s *MemoryStore) Add(_ context.Context, tenant, method string, t time.Time, usage Usage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, period := range []Period{Day, Month} {
+		key := newPeriodKey(period, t)
+		tenants, ok := s.usage[key]
+		if !ok {
+			s.prune(t)
+			tenants = make(map[string]map[string]Usage)
+			s.usage[key] = tenants
+		}
+		methods, ok := tenants[tenant]
+		if !ok {
+			methods = make(map[string]Usage)
+			tenants[tenant] = methods
+		}
+		methodUsage := methods[method]
+		methodUsage.Add(usage)
+		methods[method] = methodUsage
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, tenant string, period Period, t time.Time) (map[string]Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	methods := s.usage[newPeriodKey(period, t)][tenant]
+	out := make(map[string]Usage, len(methods))
+	for method, usage := range methods {
+		out[method] = usage
+	}
+	return out, nil
+}
+
+// Tenants implements Store.
+func (s *MemoryStore) Tenants(_ context.Context, period Period, t time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tenants := s.usage[newPeriodKey(period, t)]
+	out := make([]string, 0, len(tenants))
+	for tenant := range tenants {
+		out = append(out, tenant)
+	}
+	sort.Strings(out)
+	return out, nil
+}
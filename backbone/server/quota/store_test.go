@@ -0,0 +1,122 @@
+package quota
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Date(2023, time.December, 6, 18, 0, 0, 0, time.UTC)
+	nextDay := now.Add(24 * time.Hour)
+	nextMonth := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, add := range []struct {
+		tenant, method string
+		t              time.Time
+		usage          Usage
+	}{
+		{"alice", "/foo.Foo/Get", now, Usage{Requests: 1, ReceivedBytes: 10, SentBytes: 100}},
+		{"alice", "/foo.Foo/Get", now, Usage{Requests: 1, ReceivedBytes: 20, SentBytes: 200}},
+		{"alice", "/foo.Foo/List", nextDay, Usage{Requests: 1, SentBytes: 1000}},
+		{"bob", "/foo.Foo/Get", now, Usage{Requests: 1}},
+		{"bob", "/foo.Foo/Get", nextMonth, Usage{Requests: 1}},
+	} {
+		if err := store.Add(ctx, add.tenant, add.method, add.t, add.usage); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		tenant   string
+		period   Period
+		t        time.Time
+		expected map[string]Usage
+	}{
+		{"alice", Day, now, map[string]Usage{
+			"/foo.Foo/Get": {Requests: 2, ReceivedBytes: 30, SentBytes: 300},
+		}},
+		{"alice", Day, nextDay, map[string]Usage{
+			"/foo.Foo/List": {Requests: 1, SentBytes: 1000},
+		}},
+		{"alice", Month, now, map[string]Usage{
+			"/foo.Foo/Get":  {Requests: 2, ReceivedBytes: 30, SentBytes: 300},
+			"/foo.Foo/List": {Requests: 1, SentBytes: 1000},
+		}},
+		{"alice", Month, nextMonth, map[string]Usage{}},
+		{"bob", Month, nextMonth, map[string]Usage{
+			"/foo.Foo/Get": {Requests: 1},
+		}},
+		{"carol", Day, now, map[string]Usage{}},
+	} {
+		usage, err := store.Get(ctx, tc.tenant, tc.period, tc.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(usage, tc.expected) {
+			t.Errorf("%s usage of %s on %s is %v, want %v", tc.period, tc.tenant, tc.t.Format("2006-01-02"), usage, tc.expected)
+		}
+	}
+
+	for _, tc := range []struct {
+		period   Period
+		t        time.Time
+		expected []string
+	}{
+		{Day, now, []string{"alice", "bob"}},
+		{Day, nextDay, []string{"alice"}},
+		{Month, now, []string{"alice", "bob"}},
+		{Month, nextMonth, []string{"bob"}},
+		{Day, now.Add(-24 * time.Hour), []string{}},
+	} {
+		tenants, err := store.Tenants(ctx, tc.period, tc.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tenants, tc.expected) {
+			t.Errorf("%s tenants on %s are %v, want %v", tc.period, tc.t.Format("2006-01-02"), tenants, tc.expected)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+
+	ctx := context.Background()
+	now := time.Date(2023, time.December, 6, 18, 0, 0, 0, time.UTC)
+	later := now.Add(DailyRetention + 24*time.Hour)
+	if err := store.Add(ctx, "alice", "/foo.Foo/Get", later, Usage{Requests: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if usage, _ := store.Get(ctx, "alice", Day, now); len(usage) != 0 {
+		t.Errorf("daily usage past retention was not dropped: %v", usage)
+	}
+	if usage, _ := store.Get(ctx, "alice", Month, now); len(usage) == 0 {
+		t.Error("monthly usage within retention was dropped")
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	testStore(t, NewRedisStore(client, "test:"))
+
+	for key, expected := range map[string]time.Duration{
+		"test:usage:2023-12-06:alice": DailyRetention,
+		"test:usage:2023-12:alice":    MonthlyRetention,
+		"test:tenants:2023-12-06":     DailyRetention,
+		"test:tenants:2023-12":        MonthlyRetention,
+	} {
+		if ttl := mr.TTL(key); ttl != expected {
+			t.Errorf("TTL of %s is %s, want %s", key, ttl, expected)
+		}
+	}
+}
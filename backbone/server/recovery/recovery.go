@@ -3,12 +3,17 @@ package recovery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net"
 	"net/http"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"htdvisser.dev/exp/backbone/server"
 	"htdvisser.dev/exp/backbone/server/packet"
@@ -17,6 +22,9 @@ import (
 
 // Middleware is middleware for panic recovery.
 type Middleware struct {
+	clock               clock.Clock
+	logger              *log.Logger
+	sinks               []Sink
 	panicToError        func(ctx context.Context, p interface{}) error
 	errorToHTTPResponse func(w http.ResponseWriter, r *http.Request, err error)
 }
@@ -46,9 +54,30 @@ func WithErrorToHTTPResponse(f func(w http.ResponseWriter, r *http.Request, err
 	})
 }
 
+// WithSink returns an option that adds sinks for panic reports.
+// If no sinks are added, panic reports are written to the default logger.
+func WithSink(sinks ...Sink) Option {
+	return option(func(opts *Middleware) {
+		opts.sinks = append(opts.sinks, sinks...)
+	})
+}
+
+// WithLogger returns an option that sets the logger for errors from sinks.
+func WithLogger(logger *log.Logger) Option {
+	return option(func(opts *Middleware) {
+		opts.logger = logger
+	})
+}
+
 // NewMiddleware returns new middleware for panic recovery.
+//
+// Recovered panics are reported to the sinks with their stack trace and request
+// metadata, and are counted in the "backbone_panics" and
+// "backbone_panics_by_fingerprint" expvars.
 func NewMiddleware(opts ...Option) (*Middleware, error) {
 	m := &Middleware{
+		clock:  clock.New(),
+		logger: log.Default(),
 		panicToError: func(_ context.Context, p interface{}) error {
 			if err, ok := p.(error); ok {
 				return err
@@ -62,13 +91,24 @@ func NewMiddleware(opts ...Option) (*Middleware, error) {
 	for _, opt := range opts {
 		opt.apply(m)
 	}
+	if len(m.sinks) == 0 {
+		m.sinks = []Sink{LogSink(m.logger)}
+	}
 	return m, nil
 }
 
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
 // RecoverUnaryRPC recovers from panics in unary RPCs.
-func (m *Middleware) RecoverUnaryRPC(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+func (m *Middleware) RecoverUnaryRPC(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 	defer func() {
 		if p := recover(); p != nil {
+			m.report(ctx, p, "unary RPC", info.FullMethod, peerAddr(ctx))
 			err = m.panicToError(ctx, p)
 		}
 	}()
@@ -76,10 +116,11 @@ func (m *Middleware) RecoverUnaryRPC(ctx context.Context, req interface{}, _ *gr
 }
 
 // RecoverStreamingRPC recovers from panics in streaming RPCs.
-func (m *Middleware) RecoverStreamingRPC(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+func (m *Middleware) RecoverStreamingRPC(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 	defer func() {
 		if p := recover(); p != nil {
 			ctx := ss.Context()
+			m.report(ctx, p, "streaming RPC", info.FullMethod, peerAddr(ctx))
 			err = m.panicToError(ctx, p)
 		}
 	}()
@@ -92,6 +133,7 @@ func (m *Middleware) RecoverHTTP(next http.Handler) http.Handler {
 		defer func() {
 			if p := recover(); p != nil {
 				ctx := r.Context()
+				m.report(ctx, p, "HTTP", r.Method+" "+r.URL.Path, r.RemoteAddr)
 				err := m.panicToError(ctx, p)
 				m.errorToHTTPResponse(w, r, err)
 			}
@@ -105,6 +147,7 @@ func (m *Middleware) RecoverStream(next stream.HandlerFunc) stream.HandlerFunc {
 	return stream.HandlerFunc(func(ctx context.Context, conn net.Conn) (err error) {
 		defer func() {
 			if p := recover(); p != nil {
+				m.report(ctx, p, "stream", "", conn.RemoteAddr().String())
 				err = m.panicToError(ctx, p)
 			}
 		}()
@@ -117,6 +160,7 @@ func (m *Middleware) RecoverPacket(next packet.HandlerFunc) packet.HandlerFunc {
 	return packet.HandlerFunc(func(ctx context.Context, pkt []byte, addr net.Addr, reply func([]byte) error) (err error) {
 		defer func() {
 			if p := recover(); p != nil {
+				m.report(ctx, p, "packet", "", addr.String())
 				err = m.panicToError(ctx, p)
 			}
 		}()
@@ -124,7 +168,25 @@ func (m *Middleware) RecoverPacket(next packet.HandlerFunc) packet.HandlerFunc {
 	})
 }
 
-// Register registers the panic recovery to the server.
+// closeTimeout is the maximum time that Close waits for queued reports.
+const closeTimeout = 10 * time.Second
+
+// Close closes the sinks that have a Close(ctx) method, such as the AsyncSink,
+// which waits for reports that are still queued.
+func (m *Middleware) Close(ctx context.Context) error {
+	var errs []error
+	for _, sink := range m.sinks {
+		if closer, ok := sink.(interface{ Close(context.Context) error }); ok {
+			if err := closer.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Register registers the panic recovery to the server. The sinks are closed
+// (see Close) when the server shuts down.
 func (m *Middleware) Register(s *server.Server) error {
 	s.GRPC.AddUnaryInterceptor(m.RecoverUnaryRPC)
 	s.GRPC.AddStreamInterceptor(m.RecoverStreamingRPC)
@@ -132,6 +194,15 @@ func (m *Middleware) Register(s *server.Server) error {
 	s.InternalGRPC.AddUnaryInterceptor(m.RecoverUnaryRPC)
 	s.InternalGRPC.AddStreamInterceptor(m.RecoverStreamingRPC)
 	s.InternalHTTP.AddMiddleware(m.RecoverHTTP)
+	s.RegisterTask("panic report sinks", func(ctx context.Context) error {
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		defer cancel()
+		if err := m.Close(ctx); err != nil {
+			m.logger.Printf("Failed to close panic report sinks: %v", err)
+		}
+		return nil
+	})
 	return nil
 }
 
@@ -0,0 +1,123 @@
+package recovery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	panic("oops")
+}
+
+func TestRecoverHTTP(t *testing.T) {
+	var reports []*Report
+	m, err := NewMiddleware(WithSink(Deduplicate(SinkFunc(func(_ context.Context, report *Report) error {
+		reports = append(reports, report)
+		return nil
+	}), time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := m.RecoverHTTP(http.HandlerFunc(panickingHandler))
+
+	before := panicsTotal.Value()
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/crash", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rec.Code)
+		}
+	}
+	if panicsTotal.Value()-before != 2 {
+		t.Errorf("expected panic counter to increase by 2, got %d", panicsTotal.Value()-before)
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("expected 1 deduplicated report, got %d", len(reports))
+	}
+	report := reports[0]
+	if report.Value != "oops" || report.Handler != "HTTP" || report.Method != "GET /crash" || report.RemoteAddr == "" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if !strings.Contains(report.Stack, "panickingHandler") {
+		t.Errorf("stack does not contain the panicking function:\n%s", report.Stack)
+	}
+	if report.Fingerprint == "" {
+		t.Error("report has no fingerprint")
+	}
+}
+
+func TestAsync(t *testing.T) {
+	unblock := make(chan struct{})
+	reported := make(chan *Report)
+	sink := Async(SinkFunc(func(_ context.Context, report *Report) error {
+		<-unblock
+		reported <- report
+		return nil
+	}), 1, nil)
+
+	ctx := context.Background()
+	first, second, third := &Report{Fingerprint: "first"}, &Report{Fingerprint: "second"}, &Report{Fingerprint: "third"}
+	if err := sink.Report(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	// The second report is queued once the worker is blocked on the first report.
+	for {
+		err := sink.Report(ctx, second)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrQueueFull) {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := sink.Report(ctx, third); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	close(unblock)
+	for _, expected := range []*Report{first, second} {
+		select {
+		case report := <-reported:
+			if report != expected {
+				t.Errorf("reported %s, expected %s", report.Fingerprint, expected.Fingerprint)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s was not reported", expected.Fingerprint)
+		}
+	}
+}
+
+func TestAsyncClose(t *testing.T) {
+	var reported []string
+	sink := Async(SinkFunc(func(_ context.Context, report *Report) error {
+		time.Sleep(10 * time.Millisecond)
+		reported = append(reported, report.Fingerprint)
+		return nil
+	}), 2, nil)
+
+	ctx := context.Background()
+	for _, fingerprint := range []string{"first", "second"} {
+		if err := sink.Report(ctx, &Report{Fingerprint: fingerprint}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(reported) != 2 {
+		t.Errorf("reported %v after close, expected first and second", reported)
+	}
+	if err := sink.Report(ctx, &Report{Fingerprint: "third"}); !errors.Is(err, ErrSinkClosed) {
+		t.Errorf("expected ErrSinkClosed, got %v", err)
+	}
+	if err := sink.Close(ctx); err != nil {
+		t.Errorf("second close returned %v", err)
+	}
+}
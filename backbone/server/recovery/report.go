@@ -0,0 +1,86 @@
+package recovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"expvar"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"htdvisser.dev/exp/backbone/server/cookie"
+)
+
+// Report is a report of a recovered panic.
+type Report struct {
+	Time time.Time `json:"time"`
+	// Value is the value that was passed to panic.
+	Value string `json:"value"`
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack string `json:"stack"`
+	// Fingerprint identifies the location of the panic. Panics with the same
+	// value type and the same call stack have the same fingerprint.
+	Fingerprint string `json:"fingerprint"`
+	// Handler is the type of handler that panicked (unary RPC, streaming RPC, HTTP, stream or packet).
+	Handler string `json:"handler"`
+	// Method is the full RPC method, or the HTTP method and path.
+	Method     string `json:"method,omitempty"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	// DeviceUID and SessionUID are only set if the user allows tracking.
+	DeviceUID  string `json:"device_uid,omitempty"`
+	SessionUID string `json:"session_uid,omitempty"`
+}
+
+var (
+	panicsTotal         = expvar.NewInt("backbone_panics")
+	panicsByFingerprint = expvar.NewMap("backbone_panics_by_fingerprint")
+)
+
+// fingerprint returns a fingerprint of the panic value type and the functions in the
+// stack trace. Arguments, offsets and line numbers are left out, so that the fingerprint
+// is stable for the same panic between different goroutines and builds.
+func fingerprint(p interface{}, stack []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%T\n", p)
+	for _, line := range strings.Split(string(stack), "\n") {
+		if line == "" || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "goroutine ") {
+			continue
+		}
+		if i := strings.LastIndex(line, "("); i > 0 {
+			line = line[:i]
+		}
+		fmt.Fprintln(h, line)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+func (m *Middleware) report(ctx context.Context, p interface{}, handler, method, remoteAddr string) {
+	stack := debug.Stack()
+	report := &Report{
+		Time:        m.clock.Now().UTC(),
+		Value:       fmt.Sprint(p),
+		Stack:       string(stack),
+		Fingerprint: fingerprint(p, stack),
+		Handler:     handler,
+		Method:      method,
+		RemoteAddr:  remoteAddr,
+	}
+	if cookie.TrackingAllowedFromContext(ctx) {
+		if uid := cookie.DeviceUIDFromContext(ctx); uid != ksuid.Nil {
+			report.DeviceUID = uid.String()
+		}
+		if uid := cookie.SessionUIDFromContext(ctx); uid != ksuid.Nil {
+			report.SessionUID = uid.String()
+		}
+	}
+	panicsTotal.Add(1)
+	panicsByFingerprint.Add(report.Fingerprint, 1)
+	for _, sink := range m.sinks {
+		if err := sink.Report(ctx, report); err != nil {
+			m.logger.Printf("Failed to report panic %s: %v", report.Fingerprint, err)
+		}
+	}
+}
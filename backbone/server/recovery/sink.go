@@ -0,0 +1,200 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink is a destination for panic reports.
+type Sink interface {
+	Report(ctx context.Context, report *Report) error
+}
+
+// SinkFunc is a function that implements Sink.
+type SinkFunc func(ctx context.Context, report *Report) error
+
+// Report implements Sink.
+func (f SinkFunc) Report(ctx context.Context, report *Report) error {
+	return f(ctx, report)
+}
+
+// LogSink returns a Sink that writes panic reports to a logger.
+func LogSink(logger *log.Logger) Sink {
+	return SinkFunc(func(_ context.Context, report *Report) error {
+		logger.Printf("Recovered from panic in %s handler %s [%s]: %s\n%s",
+			report.Handler, report.Method, report.Fingerprint, report.Value, report.Stack)
+		return nil
+	})
+}
+
+// FileSink returns a Sink that appends panic reports as JSON lines to the file with the given name.
+func FileSink(name string) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(_ context.Context, report *Report) error {
+		b, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write(append(b, '\n')); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// HTTPSink returns a Sink that posts panic reports as JSON to a webhook-style HTTP endpoint.
+// If client is nil, http.DefaultClient is used.
+//
+// The returned Sink posts reports synchronously, with a timeout of 10 seconds.
+// It should be wrapped in Async, so that a slow or unavailable endpoint does not
+// block the requests that panic.
+func HTTPSink(url string, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return SinkFunc(func(_ context.Context, report *Report) error {
+		b, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		// The request context may already be canceled, but we still want to send the report.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		if res.StatusCode >= 300 {
+			return fmt.Errorf("unexpected response status %q", res.Status)
+		}
+		return nil
+	})
+}
+
+// ErrQueueFull is returned by the Sink of Async when its queue is full.
+var ErrQueueFull = errors.New("panic report queue full")
+
+// ErrSinkClosed is returned by the Sink of Async after it is closed.
+var ErrSinkClosed = errors.New("panic report sink closed")
+
+// AsyncSink is a Sink that queues reports and passes them to another Sink from
+// a separate goroutine. Use Async to create an AsyncSink.
+type AsyncSink struct {
+	sink   Sink
+	logger *log.Logger
+	queue  chan *Report
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Async returns a Sink that queues reports and passes them to sink from a separate goroutine.
+// The queue holds up to size reports. When the queue is full, reports are dropped and
+// ErrQueueFull is returned. Errors from sink are written to logger, or to the default
+// logger if logger is nil.
+//
+// The queued reports are lost if the process exits before they are passed to sink.
+// The Register func of the Middleware closes Async sinks when the server shuts down,
+// which waits for the queued reports.
+func Async(sink Sink, size int, logger *log.Logger) *AsyncSink {
+	if logger == nil {
+		logger = log.Default()
+	}
+	s := &AsyncSink{
+		sink:   sink,
+		logger: logger,
+		queue:  make(chan *Report, size),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *AsyncSink) run() {
+	defer close(s.done)
+	for report := range s.queue {
+		if err := s.sink.Report(context.Background(), report); err != nil {
+			s.logger.Printf("Failed to report panic %s: %v", report.Fingerprint, err)
+		}
+	}
+}
+
+// Report implements Sink.
+func (s *AsyncSink) Report(_ context.Context, report *Report) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrSinkClosed
+	}
+	select {
+	case s.queue <- report:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting reports, and waits until the queued reports have been
+// passed to the sink, or until ctx is done.
+func (s *AsyncSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deduplicate returns a Sink that only passes the first report of each fingerprint
+// within the given interval to sink.
+func Deduplicate(sink Sink, interval time.Duration) Sink {
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]time.Time)
+	)
+	return SinkFunc(func(ctx context.Context, report *Report) error {
+		mu.Lock()
+		for fingerprint, t := range seen {
+			if report.Time.Sub(t) >= interval {
+				delete(seen, fingerprint)
+			}
+		}
+		_, duplicate := seen[report.Fingerprint]
+		if !duplicate {
+			seen[report.Fingerprint] = report.Time
+		}
+		mu.Unlock()
+		if duplicate {
+			return nil
+		}
+		return sink.Report(ctx, report)
+	})
+}
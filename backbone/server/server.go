@@ -8,6 +8,7 @@ import (
 	"net"
 	stdhttp "net/http"
 	_ "net/http/pprof" // Registers /debug/pprof endpoints to DefaultServeMux (the internal HTTP server).
+	"sync"
 
 	"golang.org/x/sync/errgroup"
 	"htdvisser.dev/exp/backbone/server/grpc"
@@ -25,8 +26,13 @@ type Server struct {
 	InternalGRPC *grpc.Server
 	InternalHTTP *http.Server
 
-	tcpServers []tcpServer
-	udpServers []udpServer
+	mu         sync.RWMutex
+	tcpServers []*tcpServer
+	udpServers []*udpServer
+	tasks      []*task
+
+	listen       func(name, network, address string) (net.Listener, error)
+	listenPacket func(name, network, address string) (net.PacketConn, error)
 
 	runGroup   *errgroup.Group
 	runContext context.Context
@@ -42,11 +48,23 @@ func New(config Config, opts ...Option) *Server {
 	options.apply(opts...)
 	s := &Server{
 		config:       config,
+		listen:       options.Listen,
+		listenPacket: options.ListenPacket,
 		GRPC:         grpc.NewServer(options.GRPCOptions...),
 		HTTP:         http.NewServer(options.HTTPOptions...),
 		InternalGRPC: grpc.NewServer(options.InternalGRPCOptions...),
 		InternalHTTP: http.NewServer(options.InternalHTTPOptions...),
 	}
+	if s.listen == nil {
+		s.listen = func(_, network, address string) (net.Listener, error) {
+			return net.Listen(network, address)
+		}
+	}
+	if s.listenPacket == nil {
+		s.listenPacket = func(_, network, address string) (net.PacketConn, error) {
+			return net.ListenPacket(network, address)
+		}
+	}
 	channelz.Register(s.InternalGRPC)
 	s.RegisterTCPServer("gRPC", s.config.ListenGRPC, s.GRPC)
 	s.RegisterTCPServer("internal gRPC", s.config.ListenInternalGRPC, s.InternalGRPC)
@@ -65,14 +83,16 @@ func (s *Server) RegisterTCPServer(name, address string, server interface {
 		return err
 	}
 	address = addr.String()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for _, registered := range s.tcpServers {
-		if address == registered.address {
+		if addr.Port != 0 && address == registered.address {
 			return fmt.Errorf("could not register %q server: %w",
 				name, fmt.Errorf("%q already registered on %q",
 					registered.name, address))
 		}
 	}
-	s.tcpServers = append(s.tcpServers, tcpServer{name: name, address: address, server: server})
+	s.tcpServers = append(s.tcpServers, &tcpServer{name: name, address: address, server: server})
 	return nil
 }
 
@@ -86,14 +106,16 @@ func (s *Server) RegisterUDPServer(name, address string, server interface {
 		return err
 	}
 	address = addr.String()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for _, registered := range s.udpServers {
-		if address == registered.address {
+		if addr.Port != 0 && address == registered.address {
 			return fmt.Errorf("could not register %q server: %w",
 				name, fmt.Errorf("%q already registered on %q",
 					registered.name, address))
 		}
 	}
-	s.udpServers = append(s.udpServers, udpServer{name: name, address: address, server: server})
+	s.udpServers = append(s.udpServers, &udpServer{name: name, address: address, server: server})
 	return nil
 }
 
@@ -117,13 +139,17 @@ func (s *Server) Run(ctx context.Context) (err error) {
 	if err = s.runUDPServers(ctx); err != nil {
 		return err
 	}
+	s.runTasks()
 	<-s.runContext.Done()
 	return s.runContext.Err()
 }
 
 func (s *Server) runTCPServers(ctx context.Context) error {
-	for _, tcpServer := range s.tcpServers {
-		if err := s.runTCPServer(ctx, tcpServer.name, tcpServer.address, tcpServer.server); err != nil {
+	s.mu.RLock()
+	tcpServers := s.tcpServers
+	s.mu.RUnlock()
+	for _, tcpServer := range tcpServers {
+		if err := s.runTCPServer(ctx, tcpServer); err != nil {
 			return err
 		}
 	}
@@ -131,79 +157,164 @@ func (s *Server) runTCPServers(ctx context.Context) error {
 }
 
 func (s *Server) runUDPServers(ctx context.Context) error {
-	for _, udpServer := range s.udpServers {
-		if err := s.runUDPServer(ctx, udpServer.name, udpServer.address, udpServer.server); err != nil {
+	s.mu.RLock()
+	udpServers := s.udpServers
+	s.mu.RUnlock()
+	for _, udpServer := range udpServers {
+		if err := s.runUDPServer(ctx, udpServer); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+type task struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// RegisterTask registers the named background task that runs while the server runs.
+// The context that is passed to run is canceled when the server shuts down, and
+// the server waits for the task to return before Run returns.
+// If the task returns an error (other than the context error), the server shuts down.
+func (s *Server) RegisterTask(name string, run func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, &task{name: name, run: run})
+}
+
+func (s *Server) runTasks() {
+	s.mu.RLock()
+	tasks := s.tasks
+	s.mu.RUnlock()
+	ctx := s.runContext
+	for _, task := range tasks {
+		task := task
+		log.Printf("Running %s task...", task.name)
+		s.runGroup.Go(func() error {
+			if err := task.run(ctx); err != nil && ctx.Err() == nil {
+				return fmt.Errorf("%s task failed: %w", task.name, err)
+			}
+			return nil
+		})
+	}
+}
+
 type tcpServer struct {
-	name    string
-	address string
-	server  interface {
+	name          string
+	address       string
+	listenAddress string
+	server        interface {
 		Serve(lis net.Listener) error
 		GracefulStop() error
 	}
 }
 
-func (s *Server) runTCPServer(ctx context.Context, name, address string, server interface {
-	Serve(lis net.Listener) error
-	GracefulStop() error
-}) error {
+// runTCPServer runs a registered TCP server.
+func (s *Server) runTCPServer(ctx context.Context, tcpServer *tcpServer) error {
 	go func() {
 		<-ctx.Done()
 		s.runGroup.Go(func() error {
-			log.Printf("Gracefully stopping %s server...", name)
-			server.GracefulStop()
+			log.Printf("Gracefully stopping %s server...", tcpServer.name)
+			tcpServer.server.GracefulStop()
 			return nil
 		})
 	}()
-	if address != "" {
-		lis, err := net.Listen("tcp", address)
+	if tcpServer.address != "" {
+		lis, err := s.listen(tcpServer.name, "tcp", tcpServer.address)
 		if err != nil {
 			return err
 		}
-		log.Printf("Serving %s on %s...", name, lis.Addr().String())
+		if lis == nil {
+			return nil
+		}
+		s.mu.Lock()
+		tcpServer.listenAddress = lis.Addr().String()
+		s.mu.Unlock()
+		log.Printf("Serving %s on %s...", tcpServer.name, lis.Addr().String())
 		s.runGroup.Go(func() error {
-			return server.Serve(lis)
+			return tcpServer.server.Serve(lis)
 		})
 	}
 	return nil
 }
 
 type udpServer struct {
-	name    string
-	address string
-	server  interface {
+	name          string
+	address       string
+	listenAddress string
+	server        interface {
 		Serve(conn net.PacketConn) error
 		GracefulStop() error
 	}
 }
 
-// runUDPServer runs a named UDP server on the given address.
-func (s *Server) runUDPServer(ctx context.Context, name, address string, server interface {
-	Serve(conn net.PacketConn) error
-	GracefulStop() error
-}) error {
+// runUDPServer runs a registered UDP server.
+func (s *Server) runUDPServer(ctx context.Context, udpServer *udpServer) error {
 	go func() {
 		<-ctx.Done()
 		s.runGroup.Go(func() error {
-			log.Printf("Gracefully stopping %s server...", name)
-			server.GracefulStop()
+			log.Printf("Gracefully stopping %s server...", udpServer.name)
+			udpServer.server.GracefulStop()
 			return nil
 		})
 	}()
-	if address != "" {
-		lis, err := net.ListenPacket("udp", address)
+	if udpServer.address != "" {
+		lis, err := s.listenPacket(udpServer.name, "udp", udpServer.address)
 		if err != nil {
 			return err
 		}
-		log.Printf("Serving %s on %s...", name, lis.LocalAddr().String())
+		if lis == nil {
+			return nil
+		}
+		s.mu.Lock()
+		udpServer.listenAddress = lis.LocalAddr().String()
+		s.mu.Unlock()
+		log.Printf("Serving %s on %s...", udpServer.name, lis.LocalAddr().String())
 		s.runGroup.Go(func() error {
-			return server.Serve(lis)
+			return udpServer.server.Serve(lis)
 		})
 	}
 	return nil
 }
+
+// ServerInfo describes a TCP or UDP server that is registered to the server.
+type ServerInfo struct {
+	// Name is the name of the server.
+	Name string
+	// Network is "tcp" or "udp".
+	Network string
+	// Address is the address that the server was registered on.
+	Address string
+	// ListenAddress is the address that the server is listening on.
+	// It is empty if the server is not (yet) listening.
+	ListenAddress string
+	// Server is the registered server.
+	Server interface{ GracefulStop() error }
+}
+
+// Servers returns information about the registered TCP and UDP servers.
+func (s *Server) Servers() []ServerInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	servers := make([]ServerInfo, 0, len(s.tcpServers)+len(s.udpServers))
+	for _, tcpServer := range s.tcpServers {
+		servers = append(servers, ServerInfo{
+			Name:          tcpServer.name,
+			Network:       "tcp",
+			Address:       tcpServer.address,
+			ListenAddress: tcpServer.listenAddress,
+			Server:        tcpServer.server,
+		})
+	}
+	for _, udpServer := range s.udpServers {
+		servers = append(servers, ServerInfo{
+			Name:          udpServer.name,
+			Network:       "udp",
+			Address:       udpServer.address,
+			ListenAddress: udpServer.listenAddress,
+			Server:        udpServer.server,
+		})
+	}
+	return servers
+}
@@ -0,0 +1,259 @@
+// Package static provides an HTTP handler for serving static assets from an fs.FS,
+// such as an embed.FS with a built frontend.
+package static
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"htdvisser.dev/exp/backbone/server"
+)
+
+type options struct {
+	prefix           string
+	index            string
+	spaFallback      bool
+	immutablePattern *regexp.Regexp
+}
+
+// Option is an option for the static handler.
+type Option interface {
+	apply(*options)
+}
+
+type option func(*options)
+
+func (f option) apply(opts *options) {
+	f(opts)
+}
+
+// WithPrefix returns an option that sets the path prefix under which the handler
+// is registered. The prefix is stripped from request paths. Requests for the prefix
+// without trailing slash are redirected to the prefix with trailing slash.
+func WithPrefix(prefix string) Option {
+	return option(func(opts *options) {
+		if prefix = strings.Trim(prefix, "/"); prefix != "" {
+			opts.prefix = "/" + prefix + "/"
+		} else {
+			opts.prefix = "/"
+		}
+	})
+}
+
+// WithIndex returns an option that sets the name of index files.
+func WithIndex(index string) Option {
+	return option(func(opts *options) {
+		opts.index = index
+	})
+}
+
+// WithSPAFallback returns an option that serves the root index file for paths
+// that don't exist and don't have an extension, so that frontends can do
+// client-side routing.
+func WithSPAFallback(spaFallback bool) Option {
+	return option(func(opts *options) {
+		opts.spaFallback = spaFallback
+	})
+}
+
+// WithImmutablePattern returns an option that sets the pattern for filenames
+// that contain a content hash. These files are cached forever by clients.
+func WithImmutablePattern(pattern *regexp.Regexp) Option {
+	return option(func(opts *options) {
+		opts.immutablePattern = pattern
+	})
+}
+
+// DefaultImmutablePattern matches filenames with a hash of at least 8 hex
+// characters, such as "app.0123abcd.js" or "app-0123abcd.js".
+var DefaultImmutablePattern = regexp.MustCompile(`[.-][0-9a-fA-F]{8,}\.[^/]+$`)
+
+// Handler serves static assets from an fs.FS.
+type Handler struct {
+	fsys    fs.FS
+	options *options
+
+	mu    sync.Mutex
+	etags map[string]string
+}
+
+// NewHandler returns a new handler that serves static assets from fsys.
+//
+// If the request accepts br or gzip encoding, and the file has a precompressed
+// variant with a ".br" or ".gz" extension, that variant is served instead.
+// Responses have strong ETags based on the content hash of the files. Since
+// the ETags are computed once, the files in fsys are not expected to change.
+// Files that match the immutable pattern are cached forever, other files
+// need to be revalidated by clients.
+func NewHandler(fsys fs.FS, opts ...Option) *Handler {
+	options := &options{
+		prefix:           "/",
+		index:            "index.html",
+		immutablePattern: DefaultImmutablePattern,
+	}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	return &Handler{
+		fsys:    fsys,
+		options: options,
+		etags:   make(map[string]string),
+	}
+}
+
+// Register registers the handler to the HTTP router of a backbone server.
+// Since the handler matches all paths under its prefix, it should be registered
+// after other routes.
+func (h *Handler) Register(s *server.Server) {
+	var handler http.Handler = h
+	if h.options.prefix != "/" {
+		bare := strings.TrimSuffix(h.options.prefix, "/")
+		s.HTTP.Router.Path(bare).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target := h.options.prefix
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+		})
+		handler = http.StripPrefix(bare, h)
+	}
+	s.HTTP.Router.PathPrefix(h.options.prefix).Handler(handler)
+}
+
+// Register registers a new handler that serves static assets from fsys
+// to the HTTP router of a backbone server.
+func Register(s *server.Server, fsys fs.FS, opts ...Option) {
+	NewHandler(fsys, opts...).Register(s)
+}
+
+var encodings = []struct {
+	name      string
+	extension string
+}{
+	{name: "br", extension: ".br"},
+	{name: "gzip", extension: ".gz"},
+}
+
+// qValue returns the quality value in the parameters of an Accept-Encoding element.
+// Invalid quality values are treated as 0 (not acceptable).
+func qValue(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(strings.TrimSpace(key), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
+
+// acceptsEncoding returns whether the Accept-Encoding header of the request accepts
+// encoding with a non-zero quality value, either explicitly or with a wildcard.
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	wildcard := -1.0
+	for _, accepted := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		accepted, params, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+		switch accepted = strings.TrimSpace(accepted); {
+		case strings.EqualFold(accepted, encoding):
+			return qValue(params) > 0
+		case accepted == "*":
+			wildcard = qValue(params)
+		}
+	}
+	return wildcard > 0
+}
+
+func isFile(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+// resolve returns the name of the file in the filesystem for the request path.
+func (h *Handler) resolve(urlPath string) (string, bool) {
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if name == "" {
+		name = "."
+	}
+	if isFile(h.fsys, name) {
+		return name, true
+	}
+	if index := path.Join(name, h.options.index); isFile(h.fsys, index) {
+		return index, true
+	}
+	if h.options.spaFallback && path.Ext(name) == "" && isFile(h.fsys, h.options.index) {
+		return h.options.index, true
+	}
+	return "", false
+}
+
+func (h *Handler) etag(name string, content []byte) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if etag, ok := h.etags[name]; ok {
+		return etag
+	}
+	sum := sha256.Sum256(content)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	h.etags[name] = etag
+	return etag
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name, ok := h.resolve(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+	served := name
+	for _, encoding := range encodings {
+		if acceptsEncoding(r, encoding.name) && isFile(h.fsys, name+encoding.extension) {
+			served = name + encoding.extension
+			w.Header().Set("Content-Encoding", encoding.name)
+			break
+		}
+	}
+
+	content, err := fs.ReadFile(h.fsys, served)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("ETag", h.etag(served, content))
+	if h.options.immutablePattern != nil && h.options.immutablePattern.MatchString(name) {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+}
@@ -0,0 +1,126 @@
+package static
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"htdvisser.dev/exp/backbone/server"
+	serverhttp "htdvisser.dev/exp/backbone/server/http"
+)
+
+func TestHandler(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":             {Data: []byte("<html></html>")},
+		"app.0123abcd.js":        {Data: []byte("console.log('hello')")},
+		"app.0123abcd.js.br":     {Data: []byte("brotli")},
+		"app.0123abcd.js.gz":     {Data: []byte("gzip")},
+		"assets/logo.svg":        {Data: []byte("<svg></svg>")},
+		"assets/docs/index.html": {Data: []byte("<html>docs</html>")},
+	}
+	h := NewHandler(fsys, WithSPAFallback(true))
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/app.0123abcd.js", http.Header{"Accept-Encoding": {"gzip, br"}})
+	if rec.Body.String() != "brotli" || rec.Header().Get("Content-Encoding") != "br" {
+		t.Errorf("expected brotli variant, got %q with encoding %q", rec.Body.String(), rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Header().Get("Content-Type") != "text/javascript; charset=utf-8" {
+		t.Errorf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+	if rec.Header().Get("Cache-Control") != "public, max-age=31536000, immutable" {
+		t.Errorf("expected immutable caching, got %q", rec.Header().Get("Cache-Control"))
+	}
+
+	rec = get("/app.0123abcd.js", http.Header{"Accept-Encoding": {"gzip"}})
+	if rec.Body.String() != "gzip" || rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("expected gzip variant, got %q with encoding %q", rec.Body.String(), rec.Header().Get("Content-Encoding"))
+	}
+
+	rec = get("/assets/logo.svg", nil)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("unexpected response for logo: %d %v", rec.Code, rec.Header())
+	}
+	if rec = get("/assets/logo.svg", http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for matching ETag, got %d", rec.Code)
+	}
+
+	if rec = get("/assets/docs/", nil); rec.Body.String() != "<html>docs</html>" {
+		t.Errorf("expected docs index, got %q", rec.Body.String())
+	}
+	if rec = get("/some/client/route", nil); rec.Code != http.StatusOK || rec.Body.String() != "<html></html>" {
+		t.Errorf("expected index fallback, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec = get("/missing.css", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for missing asset, got %d", rec.Code)
+	}
+}
+
+func TestAcceptsEncoding(t *testing.T) {
+	for header, expected := range map[string]bool{
+		"":                    false,
+		"gzip":                true,
+		"GZIP":                true,
+		"br, gzip;q=0.5":      true,
+		"gzip;q=0":            false,
+		"gzip; q=0.0":         false,
+		"gzip;q=0.000":        false,
+		"gzip;q=0.001":        true,
+		"gzip;q=invalid":      false,
+		"*":                   true,
+		"*;q=0":               false,
+		"gzip;q=0, *":         false,
+		"br, *;q=0.1":         true,
+		"identity, deflate":   false,
+		"x-gzip, gzip;q=1.00": true,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", header)
+		if actual := acceptsEncoding(req, "gzip"); actual != expected {
+			t.Errorf("acceptsEncoding(%q, gzip) was %v, expected %v", header, actual, expected)
+		}
+	}
+}
+
+func TestRegisterPrefix(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":      {Data: []byte("<html></html>")},
+		"assets/logo.svg": {Data: []byte("<svg></svg>")},
+	}
+	for _, prefix := range []string{"/app", "app/", "/app/"} {
+		s := server.New(server.Config{}, server.WithInternalHTTPOptions(
+			serverhttp.WithServeMux(http.NewServeMux()),
+		))
+		Register(s, fsys, WithPrefix(prefix))
+
+		get := func(path string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			s.HTTP.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			return rec
+		}
+
+		if rec := get("/app?lang=en"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/app/?lang=en" {
+			t.Errorf("prefix %q: expected redirect to /app/, got %d %q", prefix, rec.Code, rec.Header().Get("Location"))
+		}
+		if rec := get("/app/"); rec.Code != http.StatusOK || rec.Body.String() != "<html></html>" {
+			t.Errorf("prefix %q: expected index, got %d %q", prefix, rec.Code, rec.Body.String())
+		}
+		if rec := get("/app/assets/logo.svg"); rec.Code != http.StatusOK || rec.Body.String() != "<svg></svg>" {
+			t.Errorf("prefix %q: expected logo, got %d %q", prefix, rec.Code, rec.Body.String())
+		}
+		if rec := get("/application"); rec.Code != http.StatusNotFound {
+			t.Errorf("prefix %q: expected 404 for path that only shares the prefix, got %d", prefix, rec.Code)
+		}
+	}
+}
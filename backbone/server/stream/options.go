@@ -1,11 +1,16 @@
 package stream
 
-import "github.com/pires/go-proxyproto"
+import (
+	"crypto/tls"
+
+	"github.com/pires/go-proxyproto"
+)
 
 type options struct {
 	middleware    []Middleware
 	proxyProtocol bool
 	proxyPolicy   proxyproto.PolicyFunc
+	tlsConfig     *tls.Config
 }
 
 func (o *options) apply(opts ...Option) {
@@ -3,6 +3,7 @@ package stream
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"sync"
 	"time"
@@ -23,9 +24,11 @@ type Server struct {
 
 	mu        sync.Mutex
 	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]ConnInfo
 
 	proxyProtocol bool
 	proxyPolicy   proxyproto.PolicyFunc
+	tlsConfig     *tls.Config
 }
 
 // NewServer instantiates a new stream server with the given options.
@@ -36,8 +39,10 @@ func NewServer(handler Handler, opts ...Option) *Server {
 		handler:       handler,
 		middleware:    options.middleware,
 		listeners:     make(map[net.Listener]struct{}),
+		conns:         make(map[net.Conn]ConnInfo),
 		proxyProtocol: options.proxyProtocol,
 		proxyPolicy:   options.proxyPolicy,
+		tlsConfig:     options.tlsConfig,
 	}
 	s.chain = chain(s.handler, s.middleware...)
 	return s
@@ -83,6 +88,8 @@ func (s *Server) Serve(lis net.Listener) error {
 			return err
 		}
 		backoff = 0
+		s.trackConn(conn)
+		tracked := conn
 		if s.extendContextWithConn != nil {
 			connCtx = s.extendContextWithConn(connCtx, conn)
 			if connCtx == nil {
@@ -98,17 +105,57 @@ func (s *Server) Serve(lis net.Listener) error {
 		if s.proxyProtocol {
 			conn, err = s.withProxy(conn)
 			if err != nil {
-				conn.Close()
+				s.untrackConn(tracked)
+				tracked.Close()
 				return err
 			}
 		}
+		if s.tlsConfig != nil {
+			conn = tls.Server(conn, s.tlsConfig)
+		}
 		go func(ctx context.Context) {
+			defer s.untrackConn(tracked)
 			defer conn.Close()
 			s.chain.HandleStream(ctx, conn)
 		}(connCtx)
 	}
 }
 
+// ConnInfo describes an active connection to the stream server.
+type ConnInfo struct {
+	LocalAddr     net.Addr
+	RemoteAddr    net.Addr
+	EstablishedAt time.Time
+}
+
+func (s *Server) trackConn(conn net.Conn) {
+	s.mu.Lock()
+	s.conns[conn] = ConnInfo{
+		LocalAddr:     conn.LocalAddr(),
+		RemoteAddr:    conn.RemoteAddr(),
+		EstablishedAt: time.Now(),
+	}
+	s.mu.Unlock()
+}
+
+func (s *Server) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+}
+
+// Conns returns information about the active connections to the stream server.
+// The remote addresses are the addresses of the peers before any PROXY protocol headers are applied.
+func (s *Server) Conns() []ConnInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conns := make([]ConnInfo, 0, len(s.conns))
+	for _, conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
 // GracefulStop stops the stream server gracefully.
 func (s *Server) GracefulStop() error {
 	s.mu.Lock()
@@ -0,0 +1,11 @@
+package stream
+
+import "crypto/tls"
+
+// WithTLS returns an option that enables TLS on connections with the given config.
+// If the PROXY protocol is enabled, the PROXY header is read before the TLS handshake.
+func WithTLS(config *tls.Config) Option {
+	return option(func(opts *options) {
+		opts.tlsConfig = config
+	})
+}
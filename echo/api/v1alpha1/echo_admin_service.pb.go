@@ -0,0 +1,765 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.14.0
+// source: echo_admin_service.proto
+
+package echo
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	_ "github.com/envoyproxy/protoc-gen-validate/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LatencyFault_Distribution int32
+
+const (
+	// The latency is always the mean.
+	LatencyFault_FIXED LatencyFault_Distribution = 0
+	// The latency is uniformly distributed between mean-jitter and mean+jitter.
+	LatencyFault_UNIFORM LatencyFault_Distribution = 1
+	// The latency is normally distributed around the mean, with jitter as standard deviation.
+	LatencyFault_NORMAL LatencyFault_Distribution = 2
+	// The latency is exponentially distributed with the mean. The jitter is not used.
+	LatencyFault_EXPONENTIAL LatencyFault_Distribution = 3
+)
+
+// Enum value maps for LatencyFault_Distribution.
+var (
+	LatencyFault_Distribution_name = map[int32]string{
+		0: "FIXED",
+		1: "UNIFORM",
+		2: "NORMAL",
+		3: "EXPONENTIAL",
+	}
+	LatencyFault_Distribution_value = map[string]int32{
+		"FIXED":       0,
+		"UNIFORM":     1,
+		"NORMAL":      2,
+		"EXPONENTIAL": 3,
+	}
+)
+
+func (x LatencyFault_Distribution) Enum() *LatencyFault_Distribution {
+	p := new(LatencyFault_Distribution)
+	*p = x
+	return p
+}
+
+func (x LatencyFault_Distribution) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LatencyFault_Distribution) Descriptor() protoreflect.EnumDescriptor {
+	return file_echo_admin_service_proto_enumTypes[0].Descriptor()
+}
+
+func (LatencyFault_Distribution) Type() protoreflect.EnumType {
+	return &file_echo_admin_service_proto_enumTypes[0]
+}
+
+func (x LatencyFault_Distribution) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LatencyFault_Distribution.Descriptor instead.
+func (LatencyFault_Distribution) EnumDescriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// The latency that is added to gRPC calls, TCP lines and UDP packets.
+type LatencyFault struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Distribution LatencyFault_Distribution `protobuf:"varint,1,opt,name=distribution,proto3,enum=htdvisser.echo.v1alpha1.LatencyFault_Distribution" json:"distribution,omitempty"`
+	Mean         *durationpb.Duration      `protobuf:"bytes,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	Jitter       *durationpb.Duration      `protobuf:"bytes,3,opt,name=jitter,proto3" json:"jitter,omitempty"`
+}
+
+func (x *LatencyFault) Reset() {
+	*x = LatencyFault{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_echo_admin_service_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LatencyFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyFault) ProtoMessage() {}
+
+func (x *LatencyFault) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_admin_service_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyFault.ProtoReflect.Descriptor instead.
+func (*LatencyFault) Descriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LatencyFault) GetDistribution() LatencyFault_Distribution {
+	if x != nil {
+		return x.Distribution
+	}
+	return LatencyFault_FIXED
+}
+
+func (x *LatencyFault) GetMean() *durationpb.Duration {
+	if x != nil {
+		return x.Mean
+	}
+	return nil
+}
+
+func (x *LatencyFault) GetJitter() *durationpb.Duration {
+	if x != nil {
+		return x.Jitter
+	}
+	return nil
+}
+
+// The errors that are returned by gRPC calls (and the HTTP gateway).
+type ErrorFault struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The fraction of calls that fail.
+	Rate float64 `protobuf:"fixed64,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	// The gRPC status code of the errors (default UNAVAILABLE).
+	Code uint32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// The message of the errors.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ErrorFault) Reset() {
+	*x = ErrorFault{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_echo_admin_service_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorFault) ProtoMessage() {}
+
+func (x *ErrorFault) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_admin_service_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorFault.ProtoReflect.Descriptor instead.
+func (*ErrorFault) Descriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ErrorFault) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *ErrorFault) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ErrorFault) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// The faults of TCP connections.
+type StreamFault struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The fraction of received lines after which the connection is reset.
+	ResetRate float64 `protobuf:"fixed64,1,opt,name=reset_rate,json=resetRate,proto3" json:"reset_rate,omitempty"`
+}
+
+func (x *StreamFault) Reset() {
+	*x = StreamFault{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_echo_admin_service_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamFault) ProtoMessage() {}
+
+func (x *StreamFault) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_admin_service_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamFault.ProtoReflect.Descriptor instead.
+func (*StreamFault) Descriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamFault) GetResetRate() float64 {
+	if x != nil {
+		return x.ResetRate
+	}
+	return 0
+}
+
+// The faults of UDP packets.
+type PacketFault struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The fraction of packets that are dropped.
+	LossRate float64 `protobuf:"fixed64,1,opt,name=loss_rate,json=lossRate,proto3" json:"loss_rate,omitempty"`
+	// The fraction of replies that are sent twice.
+	DuplicateRate float64 `protobuf:"fixed64,2,opt,name=duplicate_rate,json=duplicateRate,proto3" json:"duplicate_rate,omitempty"`
+	// The fraction of replies that are delayed by reorder_delay,
+	// so that replies to later packets overtake them.
+	ReorderRate float64 `protobuf:"fixed64,3,opt,name=reorder_rate,json=reorderRate,proto3" json:"reorder_rate,omitempty"`
+	// The delay of reordered replies (default 100ms).
+	ReorderDelay *durationpb.Duration `protobuf:"bytes,4,opt,name=reorder_delay,json=reorderDelay,proto3" json:"reorder_delay,omitempty"`
+}
+
+func (x *PacketFault) Reset() {
+	*x = PacketFault{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_echo_admin_service_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PacketFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PacketFault) ProtoMessage() {}
+
+func (x *PacketFault) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_admin_service_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PacketFault.ProtoReflect.Descriptor instead.
+func (*PacketFault) Descriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PacketFault) GetLossRate() float64 {
+	if x != nil {
+		return x.LossRate
+	}
+	return 0
+}
+
+func (x *PacketFault) GetDuplicateRate() float64 {
+	if x != nil {
+		return x.DuplicateRate
+	}
+	return 0
+}
+
+func (x *PacketFault) GetReorderRate() float64 {
+	if x != nil {
+		return x.ReorderRate
+	}
+	return 0
+}
+
+func (x *PacketFault) GetReorderDelay() *durationpb.Duration {
+	if x != nil {
+		return x.ReorderDelay
+	}
+	return nil
+}
+
+// The faults that are injected by the echo service.
+type Faults struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latency *LatencyFault `protobuf:"bytes,1,opt,name=latency,proto3" json:"latency,omitempty"`
+	Error   *ErrorFault   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Stream  *StreamFault  `protobuf:"bytes,3,opt,name=stream,proto3" json:"stream,omitempty"`
+	Packet  *PacketFault  `protobuf:"bytes,4,opt,name=packet,proto3" json:"packet,omitempty"`
+}
+
+func (x *Faults) Reset() {
+	*x = Faults{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_echo_admin_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Faults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Faults) ProtoMessage() {}
+
+func (x *Faults) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_admin_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Faults.ProtoReflect.Descriptor instead.
+func (*Faults) Descriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Faults) GetLatency() *LatencyFault {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *Faults) GetError() *ErrorFault {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *Faults) GetStream() *StreamFault {
+	if x != nil {
+		return x.Stream
+	}
+	return nil
+}
+
+func (x *Faults) GetPacket() *PacketFault {
+	if x != nil {
+		return x.Packet
+	}
+	return nil
+}
+
+// The request message for EchoAdminService.GetFaults.
+type GetFaultsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetFaultsRequest) Reset() {
+	*x = GetFaultsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_echo_admin_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFaultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFaultsRequest) ProtoMessage() {}
+
+func (x *GetFaultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_admin_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFaultsRequest.ProtoReflect.Descriptor instead.
+func (*GetFaultsRequest) Descriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{5}
+}
+
+// The request message for EchoAdminService.SetFaults.
+type SetFaultsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Faults *Faults `protobuf:"bytes,1,opt,name=faults,proto3" json:"faults,omitempty"`
+}
+
+func (x *SetFaultsRequest) Reset() {
+	*x = SetFaultsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_echo_admin_service_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetFaultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFaultsRequest) ProtoMessage() {}
+
+func (x *SetFaultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_echo_admin_service_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFaultsRequest.ProtoReflect.Descriptor instead.
+func (*SetFaultsRequest) Descriptor() ([]byte, []int) {
+	return file_echo_admin_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetFaultsRequest) GetFaults() *Faults {
+	if x != nil {
+		return x.Faults
+	}
+	return nil
+}
+
+var File_echo_admin_service_proto protoreflect.FileDescriptor
+
+var file_echo_admin_service_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x65, 0x63, 0x68, 0x6f, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17, 0x68, 0x74, 0x64, 0x76,
+	0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x1a, 0x41, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x65, 0x6e, 0x76, 0x6f, 0x79, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb3, 0x02, 0x0a, 0x0c, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x46, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x60, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x32, 0x2e, 0x68, 0x74,
+	0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x46, 0x61, 0x75,
+	0x6c, 0x74, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x08, 0xfa, 0x42, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01, 0x52, 0x0c, 0x64, 0x69, 0x73, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x42, 0x0c, 0xfa, 0x42, 0x09, 0xaa, 0x01, 0x06, 0x22, 0x02, 0x08, 0x3c, 0x32, 0x00, 0x52, 0x04,
+	0x6d, 0x65, 0x61, 0x6e, 0x12, 0x3f, 0x0a, 0x06, 0x6a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x0c, 0xfa, 0x42, 0x09, 0xaa, 0x01, 0x06, 0x22, 0x02, 0x08, 0x3c, 0x32, 0x00, 0x52, 0x06, 0x6a,
+	0x69, 0x74, 0x74, 0x65, 0x72, 0x22, 0x43, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x49, 0x58, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x49, 0x46, 0x4f, 0x52, 0x4d, 0x10, 0x01, 0x12, 0x0a, 0x0a,
+	0x06, 0x4e, 0x4f, 0x52, 0x4d, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x45, 0x58, 0x50,
+	0x4f, 0x4e, 0x45, 0x4e, 0x54, 0x49, 0x41, 0x4c, 0x10, 0x03, 0x22, 0x70, 0x0a, 0x0a, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x42, 0x17, 0xfa, 0x42, 0x14, 0x12, 0x12, 0x19, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x52,
+	0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x2a, 0x02, 0x18, 0x10, 0x52, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x45, 0x0a, 0x0b,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x36, 0x0a, 0x0a, 0x72,
+	0x65, 0x73, 0x65, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x42,
+	0x17, 0xfa, 0x42, 0x14, 0x12, 0x12, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, 0x29,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x52, 0x09, 0x72, 0x65, 0x73, 0x65, 0x74, 0x52,
+	0x61, 0x74, 0x65, 0x22, 0x8d, 0x02, 0x0a, 0x0b, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x46, 0x61,
+	0x75, 0x6c, 0x74, 0x12, 0x34, 0x0a, 0x09, 0x6c, 0x6f, 0x73, 0x73, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x42, 0x17, 0xfa, 0x42, 0x14, 0x12, 0x12, 0x19, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x52,
+	0x08, 0x6c, 0x6f, 0x73, 0x73, 0x52, 0x61, 0x74, 0x65, 0x12, 0x3e, 0x0a, 0x0e, 0x64, 0x75, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x42, 0x17, 0xfa, 0x42, 0x14, 0x12, 0x12, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0,
+	0x3f, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x52, 0x0d, 0x64, 0x75, 0x70, 0x6c,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x3a, 0x0a, 0x0c, 0x72, 0x65, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x42,
+	0x17, 0xfa, 0x42, 0x14, 0x12, 0x12, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, 0x29,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x52, 0x0b, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x52, 0x61, 0x74, 0x65, 0x12, 0x4c, 0x0a, 0x0d, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0c, 0xfa, 0x42, 0x09, 0xaa, 0x01, 0x06, 0x22,
+	0x02, 0x08, 0x0a, 0x32, 0x00, 0x52, 0x0c, 0x72, 0x65, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x44, 0x65,
+	0x6c, 0x61, 0x79, 0x22, 0x80, 0x02, 0x0a, 0x06, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x3f,
+	0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x68, 0x74, 0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x39, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23,
+	0x2e, 0x68, 0x74, 0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x46, 0x61,
+	0x75, 0x6c, 0x74, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x3c, 0x0a, 0x06, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x74, 0x64,
+	0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x46, 0x61, 0x75, 0x6c, 0x74,
+	0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x3c, 0x0a, 0x06, 0x70, 0x61, 0x63, 0x6b,
+	0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x74, 0x64, 0x76, 0x69,
+	0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x52, 0x06,
+	0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x22, 0x12, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x46, 0x61, 0x75,
+	0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x55, 0x0a, 0x10, 0x53, 0x65,
+	0x74, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x41,
+	0x0a, 0x06, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x68, 0x74, 0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x42,
+	0x08, 0xfa, 0x42, 0x05, 0x8a, 0x01, 0x02, 0x10, 0x01, 0x52, 0x06, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x73, 0x32, 0x8a, 0x02, 0x0a, 0x10, 0x45, 0x63, 0x68, 0x6f, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x76, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x46, 0x61, 0x75,
+	0x6c, 0x74, 0x73, 0x12, 0x29, 0x2e, 0x68, 0x74, 0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e,
+	0x65, 0x63, 0x68, 0x6f, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x68, 0x74, 0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x22,
+	0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x65, 0x63, 0x68, 0x6f, 0x2f, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x7e,
+	0x0a, 0x09, 0x53, 0x65, 0x74, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x29, 0x2e, 0x68, 0x74,
+	0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x68, 0x74, 0x64, 0x76, 0x69, 0x73, 0x73,
+	0x65, 0x72, 0x2e, 0x65, 0x63, 0x68, 0x6f, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x3a,
+	0x06, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x1a, 0x15, 0x2f, 0x65, 0x63, 0x68, 0x6f, 0x2f, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x42, 0x2a,
+	0x5a, 0x28, 0x68, 0x74, 0x64, 0x76, 0x69, 0x73, 0x73, 0x65, 0x72, 0x2e, 0x64, 0x65, 0x76, 0x2f,
+	0x65, 0x78, 0x70, 0x2f, 0x65, 0x63, 0x68, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b, 0x65, 0x63, 0x68, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_echo_admin_service_proto_rawDescOnce sync.Once
+	file_echo_admin_service_proto_rawDescData = file_echo_admin_service_proto_rawDesc
+)
+
+func file_echo_admin_service_proto_rawDescGZIP() []byte {
+	file_echo_admin_service_proto_rawDescOnce.Do(func() {
+		file_echo_admin_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_echo_admin_service_proto_rawDescData)
+	})
+	return file_echo_admin_service_proto_rawDescData
+}
+
+var (
+	file_echo_admin_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+	file_echo_admin_service_proto_msgTypes  = make([]protoimpl.MessageInfo, 7)
+	file_echo_admin_service_proto_goTypes   = []interface{}{
+		(LatencyFault_Distribution)(0), // 0: htdvisser.echo.v1alpha1.LatencyFault.Distribution
+		(*LatencyFault)(nil),           // 1: htdvisser.echo.v1alpha1.LatencyFault
+		(*ErrorFault)(nil),             // 2: htdvisser.echo.v1alpha1.ErrorFault
+		(*StreamFault)(nil),            // 3: htdvisser.echo.v1alpha1.StreamFault
+		(*PacketFault)(nil),            // 4: htdvisser.echo.v1alpha1.PacketFault
+		(*Faults)(nil),                 // 5: htdvisser.echo.v1alpha1.Faults
+		(*GetFaultsRequest)(nil),       // 6: htdvisser.echo.v1alpha1.GetFaultsRequest
+		(*SetFaultsRequest)(nil),       // 7: htdvisser.echo.v1alpha1.SetFaultsRequest
+		(*durationpb.Duration)(nil),    // 8: google.protobuf.Duration
+	}
+)
+var file_echo_admin_service_proto_depIdxs = []int32{
+	0,  // 0: htdvisser.echo.v1alpha1.LatencyFault.distribution:type_name -> htdvisser.echo.v1alpha1.LatencyFault.Distribution
+	8,  // 1: htdvisser.echo.v1alpha1.LatencyFault.mean:type_name -> google.protobuf.Duration
+	8,  // 2: htdvisser.echo.v1alpha1.LatencyFault.jitter:type_name -> google.protobuf.Duration
+	8,  // 3: htdvisser.echo.v1alpha1.PacketFault.reorder_delay:type_name -> google.protobuf.Duration
+	1,  // 4: htdvisser.echo.v1alpha1.Faults.latency:type_name -> htdvisser.echo.v1alpha1.LatencyFault
+	2,  // 5: htdvisser.echo.v1alpha1.Faults.error:type_name -> htdvisser.echo.v1alpha1.ErrorFault
+	3,  // 6: htdvisser.echo.v1alpha1.Faults.stream:type_name -> htdvisser.echo.v1alpha1.StreamFault
+	4,  // 7: htdvisser.echo.v1alpha1.Faults.packet:type_name -> htdvisser.echo.v1alpha1.PacketFault
+	5,  // 8: htdvisser.echo.v1alpha1.SetFaultsRequest.faults:type_name -> htdvisser.echo.v1alpha1.Faults
+	6,  // 9: htdvisser.echo.v1alpha1.EchoAdminService.GetFaults:input_type -> htdvisser.echo.v1alpha1.GetFaultsRequest
+	7,  // 10: htdvisser.echo.v1alpha1.EchoAdminService.SetFaults:input_type -> htdvisser.echo.v1alpha1.SetFaultsRequest
+	5,  // 11: htdvisser.echo.v1alpha1.EchoAdminService.GetFaults:output_type -> htdvisser.echo.v1alpha1.Faults
+	5,  // 12: htdvisser.echo.v1alpha1.EchoAdminService.SetFaults:output_type -> htdvisser.echo.v1alpha1.Faults
+	11, // [11:13] is the sub-list for method output_type
+	9,  // [9:11] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_echo_admin_service_proto_init() }
+func file_echo_admin_service_proto_init() {
+	if File_echo_admin_service_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_echo_admin_service_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LatencyFault); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_echo_admin_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ErrorFault); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_echo_admin_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamFault); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_echo_admin_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PacketFault); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_echo_admin_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Faults); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_echo_admin_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFaultsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_echo_admin_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetFaultsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_echo_admin_service_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_echo_admin_service_proto_goTypes,
+		DependencyIndexes: file_echo_admin_service_proto_depIdxs,
+		EnumInfos:         file_echo_admin_service_proto_enumTypes,
+		MessageInfos:      file_echo_admin_service_proto_msgTypes,
+	}.Build()
+	File_echo_admin_service_proto = out.File
+	file_echo_admin_service_proto_rawDesc = nil
+	file_echo_admin_service_proto_goTypes = nil
+	file_echo_admin_service_proto_depIdxs = nil
+}
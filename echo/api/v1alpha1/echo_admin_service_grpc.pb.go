@@ -0,0 +1,151 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.14.0
+// source: echo_admin_service.proto
+
+package echo
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	EchoAdminService_GetFaults_FullMethodName = "/htdvisser.echo.v1alpha1.EchoAdminService/GetFaults"
+	EchoAdminService_SetFaults_FullMethodName = "/htdvisser.echo.v1alpha1.EchoAdminService/SetFaults"
+)
+
+// EchoAdminServiceClient is the client API for EchoAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EchoAdminServiceClient interface {
+	// GetFaults returns the faults that are currently injected.
+	GetFaults(ctx context.Context, in *GetFaultsRequest, opts ...grpc.CallOption) (*Faults, error)
+	// SetFaults replaces the faults that are injected.
+	SetFaults(ctx context.Context, in *SetFaultsRequest, opts ...grpc.CallOption) (*Faults, error)
+}
+
+type echoAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEchoAdminServiceClient(cc grpc.ClientConnInterface) EchoAdminServiceClient {
+	return &echoAdminServiceClient{cc}
+}
+
+func (c *echoAdminServiceClient) GetFaults(ctx context.Context, in *GetFaultsRequest, opts ...grpc.CallOption) (*Faults, error) {
+	out := new(Faults)
+	err := c.cc.Invoke(ctx, EchoAdminService_GetFaults_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echoAdminServiceClient) SetFaults(ctx context.Context, in *SetFaultsRequest, opts ...grpc.CallOption) (*Faults, error) {
+	out := new(Faults)
+	err := c.cc.Invoke(ctx, EchoAdminService_SetFaults_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EchoAdminServiceServer is the server API for EchoAdminService service.
+// All implementations must embed UnimplementedEchoAdminServiceServer
+// for forward compatibility
+type EchoAdminServiceServer interface {
+	// GetFaults returns the faults that are currently injected.
+	GetFaults(context.Context, *GetFaultsRequest) (*Faults, error)
+	// SetFaults replaces the faults that are injected.
+	SetFaults(context.Context, *SetFaultsRequest) (*Faults, error)
+	mustEmbedUnimplementedEchoAdminServiceServer()
+}
+
+// UnimplementedEchoAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEchoAdminServiceServer struct{}
+
+func (UnimplementedEchoAdminServiceServer) GetFaults(context.Context, *GetFaultsRequest) (*Faults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFaults not implemented")
+}
+
+func (UnimplementedEchoAdminServiceServer) SetFaults(context.Context, *SetFaultsRequest) (*Faults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFaults not implemented")
+}
+func (UnimplementedEchoAdminServiceServer) mustEmbedUnimplementedEchoAdminServiceServer() {}
+
+// UnsafeEchoAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EchoAdminServiceServer will
+// result in compilation errors.
+type UnsafeEchoAdminServiceServer interface {
+	mustEmbedUnimplementedEchoAdminServiceServer()
+}
+
+func RegisterEchoAdminServiceServer(s grpc.ServiceRegistrar, srv EchoAdminServiceServer) {
+	s.RegisterService(&EchoAdminService_ServiceDesc, srv)
+}
+
+func _EchoAdminService_GetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchoAdminServiceServer).GetFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EchoAdminService_GetFaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchoAdminServiceServer).GetFaults(ctx, req.(*GetFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EchoAdminService_SetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchoAdminServiceServer).SetFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EchoAdminService_SetFaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchoAdminServiceServer).SetFaults(ctx, req.(*SetFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EchoAdminService_ServiceDesc is the grpc.ServiceDesc for EchoAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EchoAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "htdvisser.echo.v1alpha1.EchoAdminService",
+	HandlerType: (*EchoAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFaults",
+			Handler:    _EchoAdminService_GetFaults_Handler,
+		},
+		{
+			MethodName: "SetFaults",
+			Handler:    _EchoAdminService_SetFaults_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "echo_admin_service.proto",
+}
@@ -174,3 +174,515 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = EchoResponseValidationError{}
+
+// Validate checks the field values on EchoStreamRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *EchoStreamRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if l := len(m.GetMessage()); l < 1 || l > 32 {
+		return EchoStreamRequestValidationError{
+			field:  "Message",
+			reason: "value length must be between 1 and 32 bytes, inclusive",
+		}
+	}
+
+	if m.GetCount() > 100 {
+		return EchoStreamRequestValidationError{
+			field:  "Count",
+			reason: "value must be less than or equal to 100",
+		}
+	}
+
+	if d := m.GetInterval(); d != nil {
+		dur, err := ptypes.Duration(d)
+		if err != nil {
+			return EchoStreamRequestValidationError{
+				field:  "Interval",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+		}
+
+		lte := time.Duration(60*time.Second + 0*time.Nanosecond)
+		gte := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+		if dur < gte || dur > lte {
+			return EchoStreamRequestValidationError{
+				field:  "Interval",
+				reason: "value must be inside range [0s, 1m0s]",
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// EchoStreamRequestValidationError is the validation error returned by
+// EchoStreamRequest.Validate if the designated constraints aren't met.
+type EchoStreamRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EchoStreamRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EchoStreamRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EchoStreamRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EchoStreamRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EchoStreamRequestValidationError) ErrorName() string {
+	return "EchoStreamRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e EchoStreamRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEchoStreamRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EchoStreamRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EchoStreamRequestValidationError{}
+
+// Validate checks the field values on EchoClientStreamResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *EchoClientStreamResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// EchoClientStreamResponseValidationError is the validation error returned by
+// EchoClientStreamResponse.Validate if the designated constraints aren't met.
+type EchoClientStreamResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e EchoClientStreamResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e EchoClientStreamResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e EchoClientStreamResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e EchoClientStreamResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e EchoClientStreamResponseValidationError) ErrorName() string {
+	return "EchoClientStreamResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e EchoClientStreamResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sEchoClientStreamResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = EchoClientStreamResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = EchoClientStreamResponseValidationError{}
+
+// Validate checks the field values on WhoamiRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *WhoamiRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// WhoamiRequestValidationError is the validation error returned by
+// WhoamiRequest.Validate if the designated constraints aren't met.
+type WhoamiRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WhoamiRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WhoamiRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WhoamiRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WhoamiRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WhoamiRequestValidationError) ErrorName() string { return "WhoamiRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WhoamiRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWhoamiRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WhoamiRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WhoamiRequestValidationError{}
+
+// Validate checks the field values on Values with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *Values) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// ValuesValidationError is the validation error returned by
+// Values.Validate if the designated constraints aren't met.
+type ValuesValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ValuesValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ValuesValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ValuesValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ValuesValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ValuesValidationError) ErrorName() string { return "ValuesValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ValuesValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sValues.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ValuesValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ValuesValidationError{}
+
+// Validate checks the field values on TLSInfo with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *TLSInfo) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Version
+
+	// no validation rules for CipherSuite
+
+	// no validation rules for ServerName
+
+	// no validation rules for NegotiatedProtocol
+
+	return nil
+}
+
+// TLSInfoValidationError is the validation error returned by
+// TLSInfo.Validate if the designated constraints aren't met.
+type TLSInfoValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TLSInfoValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TLSInfoValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TLSInfoValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TLSInfoValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TLSInfoValidationError) ErrorName() string { return "TLSInfoValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TLSInfoValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTLSInfo.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TLSInfoValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TLSInfoValidationError{}
+
+// Validate checks the field values on WhoamiResponse with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *WhoamiResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Protocol
+
+	// no validation rules for Hostname
+
+	// no validation rules for PeerAddress
+
+	// no validation rules for ProxyAddress
+
+	// no validation rules for LocalAddress
+
+	if v, ok := interface{}(m.GetTls()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return WhoamiResponseValidationError{
+				field:  "Tls",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for key, val := range m.GetMetadata() {
+		_ = val
+
+		// no validation rules for Metadata[key]
+
+		if v, ok := interface{}(val).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return WhoamiResponseValidationError{
+					field:  fmt.Sprintf("Metadata[%v]", key),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for key, val := range m.GetForwarded() {
+		_ = val
+
+		// no validation rules for Forwarded[key]
+
+		if v, ok := interface{}(val).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return WhoamiResponseValidationError{
+					field:  fmt.Sprintf("Forwarded[%v]", key),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if v, ok := interface{}(m.GetReceivedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return WhoamiResponseValidationError{
+				field:  "ReceivedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetTimeout()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return WhoamiResponseValidationError{
+				field:  "Timeout",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// WhoamiResponseValidationError is the validation error returned by
+// WhoamiResponse.Validate if the designated constraints aren't met.
+type WhoamiResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WhoamiResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WhoamiResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WhoamiResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WhoamiResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WhoamiResponseValidationError) ErrorName() string { return "WhoamiResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WhoamiResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWhoamiResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WhoamiResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WhoamiResponseValidationError{}
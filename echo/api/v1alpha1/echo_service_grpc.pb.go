@@ -1,4 +1,8 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.14.0
+// source: echo_service.proto
 
 package echo
 
@@ -12,13 +16,30 @@ import (
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
 const _ = grpc.SupportPackageIsVersion7
 
+const (
+	EchoService_Echo_FullMethodName             = "/htdvisser.echo.v1alpha1.EchoService/Echo"
+	EchoService_EchoStream_FullMethodName       = "/htdvisser.echo.v1alpha1.EchoService/EchoStream"
+	EchoService_EchoClientStream_FullMethodName = "/htdvisser.echo.v1alpha1.EchoService/EchoClientStream"
+	EchoService_EchoBidiStream_FullMethodName   = "/htdvisser.echo.v1alpha1.EchoService/EchoBidiStream"
+	EchoService_Whoami_FullMethodName           = "/htdvisser.echo.v1alpha1.EchoService/Whoami"
+)
+
 // EchoServiceClient is the client API for EchoService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EchoServiceClient interface {
 	Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error)
+	// EchoStream echoes the message count times, waiting interval between the echoes.
+	EchoStream(ctx context.Context, in *EchoStreamRequest, opts ...grpc.CallOption) (EchoService_EchoStreamClient, error)
+	// EchoClientStream echoes all messages of the stream in a single response.
+	EchoClientStream(ctx context.Context, opts ...grpc.CallOption) (EchoService_EchoClientStreamClient, error)
+	// EchoBidiStream echoes each message of the stream.
+	EchoBidiStream(ctx context.Context, opts ...grpc.CallOption) (EchoService_EchoBidiStreamClient, error)
+	// Whoami returns what the server observed about the request.
+	Whoami(ctx context.Context, in *WhoamiRequest, opts ...grpc.CallOption) (*WhoamiResponse, error)
 }
 
 type echoServiceClient struct {
@@ -31,7 +52,113 @@ func NewEchoServiceClient(cc grpc.ClientConnInterface) EchoServiceClient {
 
 func (c *echoServiceClient) Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error) {
 	out := new(EchoResponse)
-	err := c.cc.Invoke(ctx, "/htdvisser.echo.v1alpha1.EchoService/Echo", in, out, opts...)
+	err := c.cc.Invoke(ctx, EchoService_Echo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *echoServiceClient) EchoStream(ctx context.Context, in *EchoStreamRequest, opts ...grpc.CallOption) (EchoService_EchoStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EchoService_ServiceDesc.Streams[0], EchoService_EchoStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &echoServiceEchoStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EchoService_EchoStreamClient interface {
+	Recv() (*EchoResponse, error)
+	grpc.ClientStream
+}
+
+type echoServiceEchoStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *echoServiceEchoStreamClient) Recv() (*EchoResponse, error) {
+	m := new(EchoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *echoServiceClient) EchoClientStream(ctx context.Context, opts ...grpc.CallOption) (EchoService_EchoClientStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EchoService_ServiceDesc.Streams[1], EchoService_EchoClientStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &echoServiceEchoClientStreamClient{stream}
+	return x, nil
+}
+
+type EchoService_EchoClientStreamClient interface {
+	Send(*EchoRequest) error
+	CloseAndRecv() (*EchoClientStreamResponse, error)
+	grpc.ClientStream
+}
+
+type echoServiceEchoClientStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *echoServiceEchoClientStreamClient) Send(m *EchoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *echoServiceEchoClientStreamClient) CloseAndRecv() (*EchoClientStreamResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(EchoClientStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *echoServiceClient) EchoBidiStream(ctx context.Context, opts ...grpc.CallOption) (EchoService_EchoBidiStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EchoService_ServiceDesc.Streams[2], EchoService_EchoBidiStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &echoServiceEchoBidiStreamClient{stream}
+	return x, nil
+}
+
+type EchoService_EchoBidiStreamClient interface {
+	Send(*EchoRequest) error
+	Recv() (*EchoResponse, error)
+	grpc.ClientStream
+}
+
+type echoServiceEchoBidiStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *echoServiceEchoBidiStreamClient) Send(m *EchoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *echoServiceEchoBidiStreamClient) Recv() (*EchoResponse, error) {
+	m := new(EchoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *echoServiceClient) Whoami(ctx context.Context, in *WhoamiRequest, opts ...grpc.CallOption) (*WhoamiResponse, error) {
+	out := new(WhoamiResponse)
+	err := c.cc.Invoke(ctx, EchoService_Whoami_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +170,14 @@ func (c *echoServiceClient) Echo(ctx context.Context, in *EchoRequest, opts ...g
 // for forward compatibility
 type EchoServiceServer interface {
 	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
+	// EchoStream echoes the message count times, waiting interval between the echoes.
+	EchoStream(*EchoStreamRequest, EchoService_EchoStreamServer) error
+	// EchoClientStream echoes all messages of the stream in a single response.
+	EchoClientStream(EchoService_EchoClientStreamServer) error
+	// EchoBidiStream echoes each message of the stream.
+	EchoBidiStream(EchoService_EchoBidiStreamServer) error
+	// Whoami returns what the server observed about the request.
+	Whoami(context.Context, *WhoamiRequest) (*WhoamiResponse, error)
 	mustEmbedUnimplementedEchoServiceServer()
 }
 
@@ -52,6 +187,22 @@ type UnimplementedEchoServiceServer struct{}
 func (UnimplementedEchoServiceServer) Echo(context.Context, *EchoRequest) (*EchoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Echo not implemented")
 }
+
+func (UnimplementedEchoServiceServer) EchoStream(*EchoStreamRequest, EchoService_EchoStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EchoStream not implemented")
+}
+
+func (UnimplementedEchoServiceServer) EchoClientStream(EchoService_EchoClientStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EchoClientStream not implemented")
+}
+
+func (UnimplementedEchoServiceServer) EchoBidiStream(EchoService_EchoBidiStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EchoBidiStream not implemented")
+}
+
+func (UnimplementedEchoServiceServer) Whoami(context.Context, *WhoamiRequest) (*WhoamiResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Whoami not implemented")
+}
 func (UnimplementedEchoServiceServer) mustEmbedUnimplementedEchoServiceServer() {}
 
 // UnsafeEchoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -62,7 +213,7 @@ type UnsafeEchoServiceServer interface {
 }
 
 func RegisterEchoServiceServer(s grpc.ServiceRegistrar, srv EchoServiceServer) {
-	s.RegisterService(&_EchoService_serviceDesc, srv)
+	s.RegisterService(&EchoService_ServiceDesc, srv)
 }
 
 func _EchoService_Echo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
@@ -75,7 +226,7 @@ func _EchoService_Echo_Handler(srv interface{}, ctx context.Context, dec func(in
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/htdvisser.echo.v1alpha1.EchoService/Echo",
+		FullMethod: EchoService_Echo_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(EchoServiceServer).Echo(ctx, req.(*EchoRequest))
@@ -83,7 +234,101 @@ func _EchoService_Echo_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
-var _EchoService_serviceDesc = grpc.ServiceDesc{
+func _EchoService_EchoStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EchoStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EchoServiceServer).EchoStream(m, &echoServiceEchoStreamServer{stream})
+}
+
+type EchoService_EchoStreamServer interface {
+	Send(*EchoResponse) error
+	grpc.ServerStream
+}
+
+type echoServiceEchoStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *echoServiceEchoStreamServer) Send(m *EchoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EchoService_EchoClientStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EchoServiceServer).EchoClientStream(&echoServiceEchoClientStreamServer{stream})
+}
+
+type EchoService_EchoClientStreamServer interface {
+	SendAndClose(*EchoClientStreamResponse) error
+	Recv() (*EchoRequest, error)
+	grpc.ServerStream
+}
+
+type echoServiceEchoClientStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *echoServiceEchoClientStreamServer) SendAndClose(m *EchoClientStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *echoServiceEchoClientStreamServer) Recv() (*EchoRequest, error) {
+	m := new(EchoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _EchoService_EchoBidiStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EchoServiceServer).EchoBidiStream(&echoServiceEchoBidiStreamServer{stream})
+}
+
+type EchoService_EchoBidiStreamServer interface {
+	Send(*EchoResponse) error
+	Recv() (*EchoRequest, error)
+	grpc.ServerStream
+}
+
+type echoServiceEchoBidiStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *echoServiceEchoBidiStreamServer) Send(m *EchoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *echoServiceEchoBidiStreamServer) Recv() (*EchoRequest, error) {
+	m := new(EchoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _EchoService_Whoami_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WhoamiRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EchoServiceServer).Whoami(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EchoService_Whoami_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EchoServiceServer).Whoami(ctx, req.(*WhoamiRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EchoService_ServiceDesc is the grpc.ServiceDesc for EchoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EchoService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "htdvisser.echo.v1alpha1.EchoService",
 	HandlerType: (*EchoServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
@@ -91,7 +336,28 @@ var _EchoService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Echo",
 			Handler:    _EchoService_Echo_Handler,
 		},
+		{
+			MethodName: "Whoami",
+			Handler:    _EchoService_Whoami_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EchoStream",
+			Handler:       _EchoService_EchoStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "EchoClientStream",
+			Handler:       _EchoService_EchoClientStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "EchoBidiStream",
+			Handler:       _EchoService_EchoBidiStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "echo_service.proto",
 }
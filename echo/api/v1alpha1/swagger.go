@@ -0,0 +1,8 @@
+package echo
+
+import _ "embed"
+
+// SwaggerJSON is the Swagger 2.0 document of the echo API.
+//
+//go:embed echo_service.swagger.json
+var SwaggerJSON []byte
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+)
+
+type benchConfig struct {
+	Enabled     bool
+	Protocol    string
+	Concurrency int
+	Rate        float64
+	Duration    time.Duration
+	Timeout     time.Duration
+	Output      string
+}
+
+// maxRate is the maximum rate of a benchmark, which is one request per nanosecond.
+const maxRate = float64(time.Second)
+
+func (c benchConfig) validate() error {
+	if c.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency %d", c.Concurrency)
+	}
+	if !(c.Rate >= 0 && c.Rate <= maxRate) {
+		return fmt.Errorf("invalid rate %v", c.Rate)
+	}
+	if c.Duration <= 0 {
+		return fmt.Errorf("invalid duration %s", c.Duration)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout %s", c.Timeout)
+	}
+	return nil
+}
+
+// defaultBenchMessage is the message that is sent if no message is given.
+const defaultBenchMessage = "ping"
+
+// echoer sends a message to the echo server and waits for its echo.
+// An echoer is used by a single worker.
+type echoer interface {
+	Echo(ctx context.Context, message string) (sent, received int, err error)
+	Close() error
+}
+
+// errMismatch is returned if the echo does not end with the message that was sent.
+var errMismatch = errors.New("response does not match request")
+
+// httpStatusError is returned if the HTTP server responds with an unexpected status.
+type httpStatusError int
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d", int(e))
+}
+
+type grpcEchoer struct {
+	client echo.EchoServiceClient
+}
+
+func (e *grpcEchoer) Echo(ctx context.Context, message string) (sent, received int, err error) {
+	req := &echo.EchoRequest{Message: message}
+	res, err := e.client.Echo(ctx, req)
+	if err != nil {
+		return proto.Size(req), 0, err
+	}
+	if !strings.HasSuffix(res.GetMessage(), message) {
+		err = errMismatch
+	}
+	return proto.Size(req), proto.Size(res), err
+}
+
+func (e *grpcEchoer) Close() error { return nil }
+
+type httpEchoer struct {
+	client *http.Client
+	url    string
+}
+
+func (e *httpEchoer) Echo(ctx context.Context, message string) (sent, received int, err error) {
+	body, err := protojson.Marshal(&echo.EchoRequest{Message: message})
+	if err != nil {
+		return 0, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := e.client.Do(req)
+	if err != nil {
+		return len(body), 0, err
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return len(body), len(resBody), err
+	}
+	if res.StatusCode != http.StatusOK {
+		return len(body), len(resBody), httpStatusError(res.StatusCode)
+	}
+	var echoRes echo.EchoResponse
+	if err = protojson.Unmarshal(resBody, &echoRes); err != nil {
+		return len(body), len(resBody), err
+	}
+	if !strings.HasSuffix(echoRes.GetMessage(), message) {
+		err = errMismatch
+	}
+	return len(body), len(resBody), err
+}
+
+func (e *httpEchoer) Close() error { return nil }
+
+// tcpEchoer sends messages as lines over a single TCP connection. The connection
+// is closed after an error, and a new connection is opened for the next message.
+type tcpEchoer struct {
+	address string
+	conn    net.Conn
+	r       *bufio.Reader
+}
+
+func (e *tcpEchoer) Echo(ctx context.Context, message string) (sent, received int, err error) {
+	if e.conn == nil {
+		var d net.Dialer
+		if e.conn, err = d.DialContext(ctx, "tcp", e.address); err != nil {
+			return 0, 0, err
+		}
+		e.r = bufio.NewReader(e.conn)
+	}
+	defer func() {
+		if err != nil {
+			e.Close()
+		}
+	}()
+	if deadline, ok := ctx.Deadline(); ok {
+		e.conn.SetDeadline(deadline)
+	}
+	sent, err = io.WriteString(e.conn, message+"\n")
+	if err != nil {
+		return sent, 0, err
+	}
+	line, err := e.r.ReadString('\n')
+	if err != nil {
+		return sent, len(line), err
+	}
+	if !strings.HasSuffix(line, message+"\n") {
+		err = errMismatch
+	}
+	return sent, len(line), err
+}
+
+func (e *tcpEchoer) Close() error {
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn, e.r = nil, nil
+	return err
+}
+
+// udpEchoer sends messages as UDP packets. Each packet ends with a sequence number,
+// so that late or duplicate replies to earlier packets are skipped instead of being
+// taken as the reply to the current packet.
+type udpEchoer struct {
+	conn net.Conn
+	buf  []byte
+	seq  uint64
+}
+
+func udpPayload(message string, seq uint64) []byte {
+	return []byte(message + " " + strconv.FormatUint(seq, 10))
+}
+
+// udpSequence returns the sequence number of a reply to a packet with the message.
+func udpSequence(reply []byte, message string) (uint64, bool) {
+	sep := bytes.LastIndexByte(reply, ' ')
+	if sep < 0 || !bytes.HasSuffix(reply[:sep], []byte(message)) {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(string(reply[sep+1:]), 10, 64)
+	return seq, err == nil
+}
+
+func (e *udpEchoer) Echo(ctx context.Context, message string) (sent, received int, err error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		e.conn.SetDeadline(deadline)
+	}
+	e.seq++
+	payload := udpPayload(message, e.seq)
+	sent, err = e.conn.Write(payload)
+	if err != nil {
+		return sent, 0, err
+	}
+	for {
+		n, err := e.conn.Read(e.buf)
+		received += n
+		if err != nil {
+			return sent, received, err
+		}
+		if bytes.HasSuffix(e.buf[:n], payload) {
+			return sent, received, nil
+		}
+		if seq, ok := udpSequence(e.buf[:n], message); ok && seq < e.seq {
+			continue // Reply to an earlier packet.
+		}
+		return sent, received, errMismatch
+	}
+}
+
+func (e *udpEchoer) Close() error { return e.conn.Close() }
+
+// newEchoers returns a function that returns an echoer for each worker, and a
+// function that releases resources that are shared by the echoers.
+func newEchoers(ctx context.Context) (newEchoer func() (echoer, error), closeFunc func() error, err error) {
+	noop := func() error { return nil }
+	switch config.bench.Protocol {
+	case "grpc":
+		cc, err := dialGRPC(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		client := echo.NewEchoServiceClient(cc)
+		return func() (echoer, error) {
+			return &grpcEchoer{client: client}, nil
+		}, cc.Close, nil
+	case "http":
+		client := &http.Client{
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				MaxIdleConnsPerHost: config.bench.Concurrency,
+			},
+		}
+		url := strings.TrimSuffix(config.server.HTTPAddress, "/") + "/api/v1alpha1/echo"
+		return func() (echoer, error) {
+			return &httpEchoer{client: client, url: url}, nil
+		}, noop, nil
+	case "tcp":
+		return func() (echoer, error) {
+			return &tcpEchoer{address: config.server.TCPAddress}, nil
+		}, noop, nil
+	case "udp":
+		return func() (echoer, error) {
+			conn, err := net.Dial("udp", config.server.UDPAddress)
+			if err != nil {
+				return nil, err
+			}
+			return &udpEchoer{conn: conn, buf: make([]byte, 64*1024)}, nil
+		}, noop, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown protocol %q", config.bench.Protocol)
+	}
+}
+
+// ticks returns a channel that receives rate ticks per second until ctx is done,
+// or nil if rate is not positive.
+func ticks(ctx context.Context, rate float64) <-chan struct{} {
+	if rate <= 0 {
+		return nil
+	}
+	ch := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				select {
+				case ch <- struct{}{}:
+				default: // All workers are busy.
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// work sends messages with the echoer until ctx is done. Requests that are
+// interrupted because ctx is done are not counted.
+func work(ctx context.Context, e echoer, message string, tick <-chan struct{}, s *stats) {
+	// Connection deadlines may expire slightly before ctx is done,
+	// so the deadline of ctx is also checked directly.
+	deadline, hasDeadline := ctx.Deadline()
+	done := func() bool {
+		return ctx.Err() != nil || hasDeadline && !time.Now().Before(deadline)
+	}
+	for {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+			}
+		}
+		if done() {
+			return
+		}
+		reqCtx, cancel := context.WithTimeout(ctx, config.bench.Timeout)
+		start := time.Now()
+		sent, received, err := e.Echo(reqCtx, message)
+		latency := time.Since(start)
+		cancel()
+		if done() {
+			return
+		}
+		s.add(latency, sent, received, err)
+	}
+}
+
+// Bench runs the benchmark and writes its report to stdout.
+func Bench(ctx context.Context, message string) error {
+	if err := config.bench.validate(); err != nil {
+		return err
+	}
+	writeReport, err := reportWriter(config.bench.Output)
+	if err != nil {
+		return err
+	}
+	if message == "" {
+		message = defaultBenchMessage
+	}
+
+	newEchoer, closeEchoers, err := newEchoers(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeEchoers()
+
+	echoers := make([]echoer, config.bench.Concurrency)
+	for i := range echoers {
+		if echoers[i], err = newEchoer(); err != nil {
+			return err
+		}
+		defer echoers[i].Close()
+	}
+
+	benchCtx, cancel := context.WithTimeout(ctx, config.bench.Duration)
+	defer cancel()
+	tick := ticks(benchCtx, config.bench.Rate)
+
+	workerStats := make([]stats, len(echoers))
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i, e := range echoers {
+		wg.Add(1)
+		go func(e echoer, s *stats) {
+			defer wg.Done()
+			work(benchCtx, e, message, tick, s)
+		}(e, &workerStats[i])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var total stats
+	for i := range workerStats {
+		total.merge(&workerStats[i])
+	}
+	return writeReport(newReport(config.bench.Protocol, config.bench.Concurrency, elapsed, &total), os.Stdout)
+}
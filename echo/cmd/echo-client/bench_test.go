@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestValidateBenchConfig(t *testing.T) {
+	valid := benchConfig{Concurrency: 1, Duration: time.Second, Timeout: time.Second}
+	if err := valid.validate(); err != nil {
+		t.Errorf("valid config returned %v", err)
+	}
+	for _, modify := range []func(*benchConfig){
+		func(c *benchConfig) { c.Concurrency = 0 },
+		func(c *benchConfig) { c.Rate = -1 },
+		func(c *benchConfig) { c.Rate = 2e9 },
+		func(c *benchConfig) { c.Rate = math.NaN() },
+		func(c *benchConfig) { c.Rate = math.Inf(1) },
+		func(c *benchConfig) { c.Duration = 0 },
+		func(c *benchConfig) { c.Timeout = -time.Second },
+	} {
+		config := valid
+		modify(&config)
+		if err := config.validate(); err == nil {
+			t.Errorf("no error for invalid config %+v", config)
+		}
+	}
+}
+
+func TestUDPEchoer(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	go func() {
+		buf := make([]byte, 1024)
+		for i := 1; ; i++ {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			reply := append([]byte("<echo>: "), buf[:n]...)
+			switch i {
+			case 1: // Duplicate reply, which is read before the reply to the second packet.
+				pc.WriteTo(reply, addr)
+			case 3:
+				reply = []byte("<echo>: something else")
+			}
+			pc.WriteTo(reply, addr)
+		}
+	}()
+
+	conn, err := net.Dial("udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &udpEchoer{conn: conn, buf: make([]byte, 1024)}
+	defer e.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i, expected := range []error{nil, nil, errMismatch} {
+		if _, _, err := e.Echo(ctx, "hello world"); err != expected {
+			t.Errorf("echo %d returned %v, want %v", i+1, err, expected)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
@@ -19,12 +20,26 @@ var config struct {
 	server struct {
 		GRPCAddress string
 		GRPCTLS     bool
+		HTTPAddress string
+		TCPAddress  string
+		UDPAddress  string
 	}
+	bench benchConfig
 }
 
 func init() {
 	pflag.StringVar(&config.server.GRPCAddress, "grpc.address", "localhost:9090", "Address of the gRPC server")
 	pflag.BoolVar(&config.server.GRPCTLS, "grpc.tls", false, "Use TLS to connect to the gRPC server")
+	pflag.StringVar(&config.server.HTTPAddress, "http.address", "http://localhost:8080", "Base URL of the HTTP server")
+	pflag.StringVar(&config.server.TCPAddress, "tcp.address", "localhost:7070", "Address of the TCP server")
+	pflag.StringVar(&config.server.UDPAddress, "udp.address", "localhost:6060", "Address of the UDP server")
+	pflag.BoolVar(&config.bench.Enabled, "bench", false, "Run a benchmark instead of a single request")
+	pflag.StringVar(&config.bench.Protocol, "bench.protocol", "grpc", "Protocol to benchmark (grpc, http, tcp or udp)")
+	pflag.IntVar(&config.bench.Concurrency, "bench.concurrency", 10, "Number of concurrent workers")
+	pflag.Float64Var(&config.bench.Rate, "bench.rate", 0, "Maximum number of requests per second of all workers (0 is unlimited)")
+	pflag.DurationVar(&config.bench.Duration, "bench.duration", 10*time.Second, "Duration of the benchmark")
+	pflag.DurationVar(&config.bench.Timeout, "bench.timeout", 5*time.Second, "Timeout of each request")
+	pflag.StringVar(&config.bench.Output, "bench.output", "text", "Output format of the report (text or json)")
 }
 
 func main() {
@@ -45,14 +60,21 @@ func main() {
 	}
 }
 
-func Main(ctx context.Context, args ...string) error {
+func dialGRPC(ctx context.Context) (*grpc.ClientConn, error) {
 	if config.server.GRPCTLS {
 		ctx = bbgrpc.NewContextWithDialOptions(ctx, grpc.WithTransportCredentials(credentials.NewTLS(nil)))
 	} else {
 		ctx = bbgrpc.NewContextWithDialOptions(ctx, grpc.WithInsecure())
 	}
+	return bbgrpc.DialContext(ctx, config.server.GRPCAddress)
+}
+
+func Main(ctx context.Context, args ...string) error {
+	if config.bench.Enabled {
+		return Bench(ctx, strings.Join(args, " "))
+	}
 
-	cc, err := bbgrpc.DialContext(ctx, config.server.GRPCAddress)
+	cc, err := dialGRPC(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorCode returns the code that an error is counted under in the report.
+// Errors are counted under their gRPC status code where possible.
+func errorCode(err error) string {
+	var (
+		statusErr httpStatusError
+		netErr    net.Error
+	)
+	switch {
+	case errors.As(err, &statusErr):
+		return fmt.Sprintf("HTTP %d", int(statusErr))
+	case errors.Is(err, errMismatch):
+		return "Mismatch"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return codes.DeadlineExceeded.String()
+	case errors.As(err, &netErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return codes.Unavailable.String()
+	}
+	return status.Code(err).String()
+}
+
+// stats are the results of a benchmark worker.
+type stats struct {
+	latencies     []time.Duration
+	errors        map[string]int64
+	sentBytes     int64
+	receivedBytes int64
+}
+
+func (s *stats) add(latency time.Duration, sent, received int, err error) {
+	s.sentBytes += int64(sent)
+	s.receivedBytes += int64(received)
+	if err != nil {
+		if s.errors == nil {
+			s.errors = make(map[string]int64)
+		}
+		s.errors[errorCode(err)]++
+		return
+	}
+	s.latencies = append(s.latencies, latency)
+}
+
+func (s *stats) merge(other *stats) {
+	s.latencies = append(s.latencies, other.latencies...)
+	for code, n := range other.errors {
+		if s.errors == nil {
+			s.errors = make(map[string]int64)
+		}
+		s.errors[code] += n
+	}
+	s.sentBytes += other.sentBytes
+	s.receivedBytes += other.receivedBytes
+}
+
+// Latency is the latency of successful requests in milliseconds.
+type Latency struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	P999 float64 `json:"p99_9"`
+	Max  float64 `json:"max"`
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// percentile returns the nearest-rank percentile p of the sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func newLatency(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, latency := range sorted {
+		sum += latency
+	}
+	return Latency{
+		Min:  milliseconds(sorted[0]),
+		Mean: milliseconds(sum / time.Duration(len(sorted))),
+		P50:  milliseconds(percentile(sorted, 50)),
+		P90:  milliseconds(percentile(sorted, 90)),
+		P99:  milliseconds(percentile(sorted, 99)),
+		P999: milliseconds(percentile(sorted, 99.9)),
+		Max:  milliseconds(sorted[len(sorted)-1]),
+	}
+}
+
+// Report is the report of a benchmark.
+type Report struct {
+	Protocol               string           `json:"protocol"`
+	Concurrency            int              `json:"concurrency"`
+	Duration               float64          `json:"duration_seconds"`
+	Requests               int64            `json:"requests"`
+	Errors                 int64            `json:"errors"`
+	ErrorCodes             map[string]int64 `json:"error_codes,omitempty"`
+	RequestsPerSecond      float64          `json:"requests_per_second"`
+	SentBytesPerSecond     float64          `json:"sent_bytes_per_second"`
+	ReceivedBytesPerSecond float64          `json:"received_bytes_per_second"`
+	Latency                Latency          `json:"latency_ms"`
+}
+
+func newReport(protocol string, concurrency int, elapsed time.Duration, s *stats) *Report {
+	r := &Report{
+		Protocol:    protocol,
+		Concurrency: concurrency,
+		Duration:    elapsed.Seconds(),
+		Requests:    int64(len(s.latencies)),
+		ErrorCodes:  s.errors,
+		Latency:     newLatency(s.latencies),
+	}
+	for _, n := range s.errors {
+		r.Errors += n
+	}
+	r.Requests += r.Errors
+	if r.Duration > 0 {
+		r.RequestsPerSecond = float64(r.Requests) / r.Duration
+		r.SentBytesPerSecond = float64(s.sentBytes) / r.Duration
+		r.ReceivedBytesPerSecond = float64(s.receivedBytes) / r.Duration
+	}
+	return r
+}
+
+func formatBytes(n float64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1f GB", n/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1f MB", n/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1f kB", n/1e3)
+	default:
+		return fmt.Sprintf("%.0f B", n)
+	}
+}
+
+// WriteText writes the report as text.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Protocol:\t%s\n", r.Protocol)
+	fmt.Fprintf(tw, "Concurrency:\t%d\n", r.Concurrency)
+	fmt.Fprintf(tw, "Duration:\t%.2fs\n", r.Duration)
+	fmt.Fprintf(tw, "Requests:\t%d (%.1f/s)\n", r.Requests, r.RequestsPerSecond)
+	var errorRate float64
+	if r.Requests > 0 {
+		errorRate = 100 * float64(r.Errors) / float64(r.Requests)
+	}
+	fmt.Fprintf(tw, "Errors:\t%d (%.2f%%)\n", r.Errors, errorRate)
+	errorCodes := make([]string, 0, len(r.ErrorCodes))
+	for code := range r.ErrorCodes {
+		errorCodes = append(errorCodes, code)
+	}
+	sort.Strings(errorCodes)
+	for _, code := range errorCodes {
+		fmt.Fprintf(tw, "  %s:\t%d\n", code, r.ErrorCodes[code])
+	}
+	fmt.Fprintf(tw, "Throughput:\t%s/s sent, %s/s received\n", formatBytes(r.SentBytesPerSecond), formatBytes(r.ReceivedBytesPerSecond))
+	fmt.Fprintf(tw, "Latency:\tmin %.3fms, mean %.3fms, max %.3fms\n", r.Latency.Min, r.Latency.Mean, r.Latency.Max)
+	fmt.Fprintf(tw, "\tp50 %.3fms, p90 %.3fms, p99 %.3fms, p99.9 %.3fms\n", r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.P999)
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func reportWriter(format string) (func(*Report, io.Writer) error, error) {
+	switch format {
+	case "text":
+		return (*Report).WriteText, nil
+	case "json":
+		return (*Report).WriteJSON, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 10)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	for _, tc := range []struct {
+		latencies []time.Duration
+		p         float64
+		expected  time.Duration
+	}{
+		{sorted, 0, 1 * time.Millisecond},
+		{sorted, 10, 1 * time.Millisecond},
+		{sorted, 11, 2 * time.Millisecond},
+		{sorted, 50, 5 * time.Millisecond},
+		{sorted, 90, 9 * time.Millisecond},
+		{sorted, 99, 10 * time.Millisecond},
+		{sorted, 99.9, 10 * time.Millisecond},
+		{sorted, 100, 10 * time.Millisecond},
+		{sorted[:1], 50, 1 * time.Millisecond},
+		{sorted[:1], 99.9, 1 * time.Millisecond},
+	} {
+		if actual := percentile(tc.latencies, tc.p); actual != tc.expected {
+			t.Errorf("p%v of %d latencies is %s, want %s", tc.p, len(tc.latencies), actual, tc.expected)
+		}
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		elapsed  time.Duration
+		stats    stats
+		expected Report
+	}{
+		{
+			name:    "empty",
+			elapsed: time.Second,
+			expected: Report{
+				Protocol: "grpc", Concurrency: 2, Duration: 1,
+			},
+		},
+		{
+			name:    "latencies and errors",
+			elapsed: 2 * time.Second,
+			stats: stats{
+				latencies:     []time.Duration{4 * time.Millisecond, 1 * time.Millisecond, 3 * time.Millisecond, 2 * time.Millisecond},
+				errors:        map[string]int64{"Unavailable": 1, "Mismatch": 1},
+				sentBytes:     600,
+				receivedBytes: 1200,
+			},
+			expected: Report{
+				Protocol: "grpc", Concurrency: 2, Duration: 2,
+				Requests: 6, Errors: 2,
+				ErrorCodes:             map[string]int64{"Unavailable": 1, "Mismatch": 1},
+				RequestsPerSecond:      3,
+				SentBytesPerSecond:     300,
+				ReceivedBytesPerSecond: 600,
+				Latency: Latency{
+					Min: 1, Mean: 2.5, P50: 2, P90: 4, P99: 4, P999: 4, Max: 4,
+				},
+			},
+		},
+		{
+			name:    "no elapsed time",
+			elapsed: 0,
+			stats:   stats{latencies: []time.Duration{time.Millisecond}},
+			expected: Report{
+				Protocol: "grpc", Concurrency: 2,
+				Requests: 1,
+				Latency:  Latency{Min: 1, Mean: 1, P50: 1, P90: 1, P99: 1, P999: 1, Max: 1},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			report := newReport("grpc", 2, tc.elapsed, &tc.stats)
+			if !reflect.DeepEqual(*report, tc.expected) {
+				t.Errorf("report is %+v, want %+v", *report, tc.expected)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	var a, b stats
+	a.add(time.Millisecond, 10, 20, nil)
+	a.add(time.Millisecond, 10, 0, status.Error(codes.Unavailable, "unavailable"))
+	b.add(time.Millisecond, 10, 20, errMismatch)
+	b.add(time.Millisecond, 10, 0, httpStatusError(502))
+	b.add(time.Millisecond, 10, 0, errors.New("other"))
+	a.merge(&b)
+
+	if len(a.latencies) != 1 || a.sentBytes != 50 || a.receivedBytes != 40 {
+		t.Errorf("unexpected stats %+v", a)
+	}
+	expected := map[string]int64{"Unavailable": 1, "Mismatch": 1, "HTTP 502": 1, "Unknown": 1}
+	if !reflect.DeepEqual(a.errors, expected) {
+		t.Errorf("errors are %v, want %v", a.errors, expected)
+	}
+}
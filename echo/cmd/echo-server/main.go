@@ -12,21 +12,29 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	bbserver "htdvisser.dev/exp/backbone/server"
 	"htdvisser.dev/exp/backbone/server/grpc"
+	"htdvisser.dev/exp/backbone/server/grpc/recording"
+	bbhttp "htdvisser.dev/exp/backbone/server/http"
+	"htdvisser.dev/exp/backbone/server/openapi"
 	"htdvisser.dev/exp/backbone/server/recovery"
 	"htdvisser.dev/exp/backbone/server/reflection"
 	"htdvisser.dev/exp/clicontext"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
 	"htdvisser.dev/exp/echo/internal/server"
 	"htdvisser.dev/exp/pflagenv"
 )
 
 var config struct {
-	server bbserver.Config
-	echo   server.Config
+	server           bbserver.Config
+	recording        recording.Config
+	echo             server.Config
+	openAPIAssetsURL string
 }
 
 func init() {
 	pflag.CommandLine.AddFlagSet(config.server.Flags("", nil))
+	pflag.CommandLine.AddFlagSet(config.recording.Flags("", nil))
 	pflag.CommandLine.AddFlagSet(config.echo.Flags("", nil))
+	pflag.CommandLine.StringVar(&config.openAPIAssetsURL, "openapi.assetsURL", "https://unpkg.com", "URL from which the scripts and styles of the API explorer are loaded")
 }
 
 func main() {
@@ -56,6 +64,9 @@ func main() {
 				runtime.WithMarshalerOption(runtime.MIMEWildcard, jsonpb),
 			),
 		),
+		bbserver.WithHTTPOptions(
+			bbhttp.WithSecurityHeaders(bbhttp.DefaultSecurityHeaders()),
+		),
 	)
 
 	backbone.HTTP.ServeMux.Handle("/api/", http.StripPrefix("/api", backbone.GRPC.Gateway))
@@ -63,12 +74,35 @@ func main() {
 	reflection.Register(backbone)
 	recovery.Register(backbone)
 
+	recorder, err := recording.Register(backbone, config.recording)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if recorder != nil {
+		defer recorder.Close()
+	}
+
 	echoService, err := server.NewEchoService(config.echo)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return
 	}
-	echoService.Register(ctx, backbone)
+	if err := echoService.Register(ctx, backbone); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if err := openapi.Register(backbone,
+		openapi.WithBasePath("/api"),
+		openapi.WithInfo("Echo API", "v1alpha1"),
+		openapi.WithDocument(echo.SwaggerJSON),
+		openapi.WithProtoNames(true),
+		openapi.WithUI(openapi.SwaggerUI, config.openAPIAssetsURL),
+	); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
 
 	if err := backbone.Run(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -4,8 +4,14 @@ go 1.20
 
 replace htdvisser.dev/exp/backbone => ../backbone
 
+replace htdvisser.dev/exp/fieldpath => ../fieldpath
+
 replace htdvisser.dev/exp/stringslice => ../stringslice
 
+replace htdvisser.dev/exp/tlsconfig => ../tlsconfig
+
+replace htdvisser.dev/exp/watcher => ../watcher
+
 require (
 	github.com/envoyproxy/protoc-gen-validate v1.0.2
 	github.com/gogo/protobuf v1.3.2
@@ -22,13 +28,18 @@ require (
 	htdvisser.dev/exp/backbone v0.0.0-20231206185358-cf15410f4841
 	htdvisser.dev/exp/clicontext v1.1.0
 	htdvisser.dev/exp/pflagenv v1.0.0
+	htdvisser.dev/exp/tlsconfig v0.0.0-20231206185358-cf15410f4841
 	mvdan.cc/gofumpt v0.5.0
+	nhooyr.io/websocket v1.8.10
 )
 
 require (
+	github.com/benbjohnson/clock v1.3.5 // indirect
 	github.com/cenkalti/backoff/v4 v4.2.1 // indirect
 	github.com/desertbit/timer v0.0.0-20180107155436-c41aec40b27f // indirect
 	github.com/ghodss/yaml v1.0.0 // indirect
+	github.com/go-logr/logr v1.3.0 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/golang/glog v1.2.0 // indirect
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/gorilla/mux v1.8.1 // indirect
@@ -37,8 +48,12 @@ require (
 	github.com/improbable-eng/grpc-web v0.15.0 // indirect
 	github.com/lyft/protoc-gen-star/v2 v2.0.3 // indirect
 	github.com/rs/cors v1.10.1 // indirect
+	github.com/segmentio/ksuid v1.0.4 // indirect
 	github.com/spf13/afero v1.11.0 // indirect
 	github.com/stretchr/testify v1.8.4 // indirect
+	go.opentelemetry.io/otel v1.21.0 // indirect
+	go.opentelemetry.io/otel/metric v1.21.0 // indirect
+	go.opentelemetry.io/otel/trace v1.21.0 // indirect
 	golang.org/x/lint v0.0.0-20210508222113-6edffad5e616 // indirect
 	golang.org/x/mod v0.14.0 // indirect
 	golang.org/x/net v0.19.0 // indirect
@@ -49,5 +64,5 @@ require (
 	google.golang.org/genproto v0.0.0-20231212172506-995d672761c0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20231212172506-995d672761c0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	nhooyr.io/websocket v1.8.10 // indirect
+	htdvisser.dev/exp/fieldpath v0.0.0-20231206185358-cf15410f4841 // indirect
 )
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+)
+
+// adminService is the EchoAdminService that changes the faults of the Echo service.
+type adminService struct {
+	faults *faults
+	echo.UnimplementedEchoAdminServiceServer
+}
+
+func (as *adminService) GetFaults(ctx context.Context, _ *echo.GetFaultsRequest) (*echo.Faults, error) {
+	return as.faults.Get(), nil
+}
+
+func (as *adminService) SetFaults(ctx context.Context, req *echo.SetFaultsRequest) (*echo.Faults, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %s", err)
+	}
+	as.faults.Set(req.GetFaults())
+	return req.GetFaults(), nil
+}
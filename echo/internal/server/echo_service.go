@@ -3,7 +3,9 @@ package server
 import (
 	"bufio"
 	"context"
+	"io"
 	"net"
+	"net/http"
 	"time"
 
 	"github.com/pires/go-proxyproto"
@@ -14,6 +16,8 @@ import (
 	"htdvisser.dev/exp/backbone/server/packet"
 	"htdvisser.dev/exp/backbone/server/stream"
 	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+	"htdvisser.dev/exp/tlsconfig"
+	"nhooyr.io/websocket"
 )
 
 // Config is the configuration for the Echo service.
@@ -23,17 +27,34 @@ type Config struct {
 	TCPProxy           bool
 	TCPProxyAllowedIPs []string
 	tcpServerOptions   []stream.Option
+	ListenTLS          string
+	TLS                tlsconfig.ServerConfig
+	tlsServerOptions   []stream.Option
 	ListenUDP          string
+	WebSocketPath      string
+	WebSocketTimeout   time.Duration
+	WhoamiTCP          string
+	WhoamiTLS          string
+	WhoamiUDP          string
+	WhoamiPath         string
+	Faults             FaultsConfig
 	Prefix             string
 }
 
 // DefaultConfig returns the default configuration for the Echo service.
 func DefaultConfig() *Config {
 	return &Config{
-		ListenTCP:  ":7070",
-		TCPTimeout: time.Minute,
-		ListenUDP:  ":6060",
-		Prefix:     "<echo>: ",
+		ListenTCP:        ":7070",
+		TCPTimeout:       time.Minute,
+		TLS:              *tlsconfig.DefaultServerConfig(),
+		ListenUDP:        ":6060",
+		WebSocketPath:    "/ws",
+		WebSocketTimeout: time.Minute,
+		WhoamiTCP:        ":7071",
+		WhoamiUDP:        ":6061",
+		WhoamiPath:       "/whoami",
+		Faults:           *DefaultFaultsConfig(),
+		Prefix:           "<echo>: ",
 	}
 }
 
@@ -47,7 +68,16 @@ func (c *Config) Flags(prefix string, defaults *Config) *pflag.FlagSet {
 	flags.DurationVar(&c.TCPTimeout, prefix+"tcp.timeout", defaults.TCPTimeout, "Connection timeout for the TCP server")
 	flags.BoolVar(&c.TCPProxy, prefix+"tcp.proxy", defaults.TCPProxy, "Support PROXY protocol on the TCP server")
 	flags.StringSliceVar(&c.TCPProxyAllowedIPs, prefix+"tcp.proxy.allowed-ips", defaults.TCPProxyAllowedIPs, "Optional list of IPs/CIDRs from which PROXY headers are read")
+	flags.StringVar(&c.ListenTLS, prefix+"tls.listen", defaults.ListenTLS, "Listen address for the TLS server (disabled if empty)")
+	flags.AddFlagSet(c.TLS.Flags(prefix+"tls.", &defaults.TLS))
 	flags.StringVar(&c.ListenUDP, prefix+"udp.listen", defaults.ListenUDP, "Listen address for the UDP server")
+	flags.StringVar(&c.WebSocketPath, prefix+"ws.path", defaults.WebSocketPath, "Path of the WebSocket endpoint on the HTTP server (disabled if empty)")
+	flags.DurationVar(&c.WebSocketTimeout, prefix+"ws.timeout", defaults.WebSocketTimeout, "Connection timeout for the WebSocket endpoint")
+	flags.StringVar(&c.WhoamiTCP, prefix+"whoami.tcp.listen", defaults.WhoamiTCP, "Listen address for the TCP whoami server (disabled if empty)")
+	flags.StringVar(&c.WhoamiTLS, prefix+"whoami.tls.listen", defaults.WhoamiTLS, "Listen address for the TLS whoami server (disabled if empty)")
+	flags.StringVar(&c.WhoamiUDP, prefix+"whoami.udp.listen", defaults.WhoamiUDP, "Listen address for the UDP whoami server (disabled if empty)")
+	flags.StringVar(&c.WhoamiPath, prefix+"whoami.path", defaults.WhoamiPath, "Path of the whoami endpoint on the HTTP server (disabled if empty)")
+	flags.AddFlagSet(c.Faults.Flags(prefix+"faults.", &defaults.Faults))
 	flags.StringVar(&c.Prefix, prefix+"prefix", defaults.Prefix, "Prefix for the echo")
 	return &flags
 }
@@ -66,11 +96,26 @@ func NewEchoService(config Config) (*EchoService, error) {
 		}
 		config.tcpServerOptions = append(config.tcpServerOptions, stream.WithProxyProtocol(policy))
 	}
-	return &EchoService{config: config}, nil
+	if config.ListenTLS != "" || config.WhoamiTLS != "" {
+		tlsConfig, err := config.TLS.Load(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		config.tlsServerOptions = append(config.tlsServerOptions, config.tcpServerOptions...)
+		config.tlsServerOptions = append(config.tlsServerOptions, stream.WithTLS(tlsConfig))
+	}
+	faults, err := config.Faults.Faults()
+	if err != nil {
+		return nil, err
+	}
+	es := &EchoService{config: config}
+	es.faults.Set(faults)
+	return es, nil
 }
 
 type EchoService struct {
 	config Config
+	faults faults
 	echo.UnimplementedEchoServiceServer
 }
 
@@ -85,11 +130,39 @@ func (es *EchoService) echoBytes(in []byte) []byte {
 	return out
 }
 
-func (es *EchoService) Register(ctx context.Context, bbs *server.Server) {
-	echo.RegisterEchoServiceServer(bbs.GRPC.Server, es)
-	echo.RegisterEchoServiceHandler(ctx, bbs.GRPC.Gateway, bbs.GRPC.LoopbackConn())
+func (es *EchoService) Register(ctx context.Context, bbs *server.Server) error {
+	echo.RegisterEchoServiceServer(bbs.GRPC, es)
+	if err := echo.RegisterEchoServiceHandlerClient(ctx, bbs.GRPC.Gateway, echo.NewEchoServiceClient(bbs.GRPC.InProcessConn())); err != nil {
+		return err
+	}
+	bbs.GRPC.AddUnaryInterceptor(es.faults.UnaryServerInterceptor())
+	bbs.GRPC.AddStreamInterceptor(es.faults.StreamServerInterceptor())
+	echo.RegisterEchoAdminServiceServer(bbs.InternalGRPC, &adminService{faults: &es.faults})
+	if err := echo.RegisterEchoAdminServiceHandlerClient(ctx, bbs.InternalGRPC.Gateway, echo.NewEchoAdminServiceClient(bbs.InternalGRPC.InProcessConn())); err != nil {
+		return err
+	}
+	bbs.InternalHTTP.ServeMux.Handle("/echo/", bbs.InternalGRPC.Gateway)
 	bbs.RegisterTCPServer("Echo-TCP", es.config.ListenTCP, stream.NewServer(es, es.config.tcpServerOptions...))
+	if es.config.ListenTLS != "" {
+		bbs.RegisterTCPServer("Echo-TLS", es.config.ListenTLS, stream.NewServer(es, es.config.tlsServerOptions...))
+	}
 	bbs.RegisterUDPServer("Echo-UDP", es.config.ListenUDP, packet.NewServer(es))
+	if es.config.WebSocketPath != "" {
+		bbs.HTTP.ServeMux.HandleFunc(es.config.WebSocketPath, es.HandleWebSocket)
+	}
+	if es.config.WhoamiTCP != "" {
+		bbs.RegisterTCPServer("Whoami-TCP", es.config.WhoamiTCP, stream.NewServer(stream.HandlerFunc(es.HandleWhoamiStream), es.config.tcpServerOptions...))
+	}
+	if es.config.WhoamiTLS != "" {
+		bbs.RegisterTCPServer("Whoami-TLS", es.config.WhoamiTLS, stream.NewServer(stream.HandlerFunc(es.HandleWhoamiStream), es.config.tlsServerOptions...))
+	}
+	if es.config.WhoamiUDP != "" {
+		bbs.RegisterUDPServer("Whoami-UDP", es.config.WhoamiUDP, packet.NewServer(packet.HandlerFunc(es.HandleWhoamiPacket)))
+	}
+	if es.config.WhoamiPath != "" {
+		bbs.HTTP.ServeMux.HandleFunc(es.config.WhoamiPath, es.HandleWhoamiHTTP)
+	}
+	return nil
 }
 
 func (es *EchoService) Echo(ctx context.Context, req *echo.EchoRequest) (*echo.EchoResponse, error) {
@@ -101,6 +174,77 @@ func (es *EchoService) Echo(ctx context.Context, req *echo.EchoRequest) (*echo.E
 	}, nil
 }
 
+func (es *EchoService) EchoStream(req *echo.EchoStreamRequest, stream echo.EchoService_EchoStreamServer) error {
+	if err := req.Validate(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %s", err)
+	}
+	count := req.GetCount()
+	if count == 0 {
+		count = 1
+	}
+	interval := req.GetInterval().AsDuration()
+	for i := uint32(0); i < count; i++ {
+		if i > 0 && interval > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-stream.Context().Done():
+				timer.Stop()
+				return status.FromContextError(stream.Context().Err()).Err()
+			case <-timer.C:
+			}
+		}
+		if err := stream.Send(&echo.EchoResponse{
+			Message: es.config.Prefix + req.Message,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxClientStreamMessages is the maximum number of messages that EchoClientStream echoes.
+const maxClientStreamMessages = 100
+
+func (es *EchoService) EchoClientStream(stream echo.EchoService_EchoClientStreamServer) error {
+	var res echo.EchoClientStreamResponse
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&res)
+		}
+		if err != nil {
+			return err
+		}
+		if err := req.Validate(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid request: %s", err)
+		}
+		if len(res.Messages) >= maxClientStreamMessages {
+			return status.Errorf(codes.ResourceExhausted, "more than %d messages", maxClientStreamMessages)
+		}
+		res.Messages = append(res.Messages, es.config.Prefix+req.Message)
+	}
+}
+
+func (es *EchoService) EchoBidiStream(stream echo.EchoService_EchoBidiStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := req.Validate(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid request: %s", err)
+		}
+		if err := stream.Send(&echo.EchoResponse{
+			Message: es.config.Prefix + req.Message,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
 func (es *EchoService) HandleStream(ctx context.Context, conn net.Conn) error {
 	r := bufio.NewReader(conn)
 	for {
@@ -109,6 +253,13 @@ func (es *EchoService) HandleStream(ctx context.Context, conn net.Conn) error {
 		if err != nil {
 			return err
 		}
+		faults := es.faults.Get()
+		if chance(faults.GetStream().GetResetRate()) {
+			return resetConn(conn)
+		}
+		if err = sleep(ctx, latency(faults.GetLatency())); err != nil {
+			return err
+		}
 		conn.SetWriteDeadline(time.Now().Add(es.config.TCPTimeout))
 		if _, err = conn.Write(es.echoBytes(msg)); err != nil {
 			return err
@@ -117,8 +268,30 @@ func (es *EchoService) HandleStream(ctx context.Context, conn net.Conn) error {
 }
 
 func (es *EchoService) HandlePacket(ctx context.Context, msg []byte, addr net.Addr, reply func([]byte) error) error {
-	if es.config.Prefix != "" {
-		return reply(append(append([]byte{}, []byte(es.config.Prefix)...), msg...))
+	return es.replyPacket(es.echoBytes(msg), reply)
+}
+
+func (es *EchoService) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return // Accept already wrote the error response.
+	}
+	defer conn.Close(websocket.StatusInternalError, "")
+	for {
+		ctx, cancel := context.WithTimeout(r.Context(), es.config.WebSocketTimeout)
+		typ, msg, err := conn.Read(ctx)
+		cancel()
+		if err != nil {
+			if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+				conn.Close(websocket.StatusNormalClosure, "")
+			}
+			return
+		}
+		ctx, cancel = context.WithTimeout(r.Context(), es.config.WebSocketTimeout)
+		err = conn.Write(ctx, typ, es.echoBytes(msg))
+		cancel()
+		if err != nil {
+			return
+		}
 	}
-	return reply(es.echoBytes(msg))
 }
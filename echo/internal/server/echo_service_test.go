@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+)
+
+// startEchoService serves the Echo service over an in-memory connection and
+// returns a client for it.
+func startEchoService(t *testing.T) echo.EchoServiceClient {
+	t.Helper()
+	config := DefaultConfig()
+	config.Prefix = "> "
+	es, err := NewEchoService(*config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(es.faults.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(es.faults.StreamServerInterceptor()),
+	)
+	echo.RegisterEchoServiceServer(s, es)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return echo.NewEchoServiceClient(conn)
+}
+
+func TestEchoStream(t *testing.T) {
+	client := startEchoService(t)
+	ctx := context.Background()
+
+	recvAll := func(stream echo.EchoService_EchoStreamClient) ([]string, error) {
+		var messages []string
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				return messages, nil
+			}
+			if err != nil {
+				return messages, err
+			}
+			messages = append(messages, res.GetMessage())
+		}
+	}
+
+	for _, tc := range []struct {
+		count    uint32
+		interval time.Duration
+		expected int
+	}{
+		{0, 0, 1},
+		{3, 0, 3},
+		{3, 20 * time.Millisecond, 3},
+	} {
+		start := time.Now()
+		stream, err := client.EchoStream(ctx, &echo.EchoStreamRequest{
+			Message:  "hi",
+			Count:    tc.count,
+			Interval: durationpb.New(tc.interval),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages, err := recvAll(stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(messages) != tc.expected {
+			t.Errorf("received %d messages for count %d, want %d", len(messages), tc.count, tc.expected)
+		}
+		for _, message := range messages {
+			if message != "> hi" {
+				t.Errorf("received %q, want %q", message, "> hi")
+			}
+		}
+		if minDuration := time.Duration(tc.expected-1) * tc.interval; time.Since(start) < minDuration {
+			t.Errorf("stream with interval %s took %s, want at least %s", tc.interval, time.Since(start), minDuration)
+		}
+	}
+
+	stream, err := client.EchoStream(ctx, &echo.EchoStreamRequest{Message: "hi", Count: 101})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recvAll(stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("stream with count over 100 returned %v, want InvalidArgument", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	stream, err = client.EchoStream(ctx, &echo.EchoStreamRequest{Message: "hi", Count: 10, Interval: durationpb.New(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err := recvAll(stream)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("stream past deadline returned %v, want DeadlineExceeded", err)
+	}
+	if len(messages) != 1 {
+		t.Errorf("received %d messages before the deadline, want 1", len(messages))
+	}
+}
+
+func TestEchoClientStream(t *testing.T) {
+	client := startEchoService(t)
+
+	send := func(n int) (*echo.EchoClientStreamResponse, error) {
+		stream, err := client.EchoClientStream(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			if err := stream.Send(&echo.EchoRequest{Message: "hi"}); err != nil {
+				break // The error is returned by CloseAndRecv.
+			}
+		}
+		return stream.CloseAndRecv()
+	}
+
+	res, err := send(maxClientStreamMessages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if messages := res.GetMessages(); len(messages) != maxClientStreamMessages {
+		t.Errorf("received %d messages, want %d", len(messages), maxClientStreamMessages)
+	} else if messages[0] != "> hi" {
+		t.Errorf("received %q, want %q", messages[0], "> hi")
+	}
+
+	if _, err := send(maxClientStreamMessages + 1); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("stream of more than %d messages returned %v, want ResourceExhausted", maxClientStreamMessages, err)
+	}
+}
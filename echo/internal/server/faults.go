@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+)
+
+// FaultsConfig is the configuration of the faults that are injected by the
+// Echo service. The faults can be changed at runtime with the EchoAdminService.
+type FaultsConfig struct {
+	LatencyDistribution string
+	LatencyMean         time.Duration
+	LatencyJitter       time.Duration
+	ErrorRate           float64
+	ErrorCode           uint32
+	ErrorMessage        string
+	TCPResetRate        float64
+	UDPLossRate         float64
+	UDPDuplicateRate    float64
+	UDPReorderRate      float64
+	UDPReorderDelay     time.Duration
+}
+
+// DefaultFaultsConfig returns the default configuration of the faults,
+// which does not inject any faults.
+func DefaultFaultsConfig() *FaultsConfig {
+	return &FaultsConfig{
+		LatencyDistribution: "fixed",
+		ErrorCode:           uint32(codes.Unavailable),
+		UDPReorderDelay:     defaultReorderDelay,
+	}
+}
+
+// Flags returns a flagset that can be added to the command line.
+func (c *FaultsConfig) Flags(prefix string, defaults *FaultsConfig) *pflag.FlagSet {
+	var flags pflag.FlagSet
+	if defaults == nil {
+		defaults = DefaultFaultsConfig()
+	}
+	flags.StringVar(&c.LatencyDistribution, prefix+"latency.distribution", defaults.LatencyDistribution, "Distribution of the injected latency (fixed, uniform, normal or exponential)")
+	flags.DurationVar(&c.LatencyMean, prefix+"latency.mean", defaults.LatencyMean, "Mean of the injected latency")
+	flags.DurationVar(&c.LatencyJitter, prefix+"latency.jitter", defaults.LatencyJitter, "Jitter of the injected latency (half width of uniform, standard deviation of normal)")
+	flags.Float64Var(&c.ErrorRate, prefix+"error.rate", defaults.ErrorRate, "Fraction of gRPC calls that fail")
+	flags.Uint32Var(&c.ErrorCode, prefix+"error.code", defaults.ErrorCode, "gRPC status code of injected errors")
+	flags.StringVar(&c.ErrorMessage, prefix+"error.message", defaults.ErrorMessage, "Message of injected errors")
+	flags.Float64Var(&c.TCPResetRate, prefix+"tcp.reset-rate", defaults.TCPResetRate, "Fraction of TCP lines after which the connection is reset")
+	flags.Float64Var(&c.UDPLossRate, prefix+"udp.loss-rate", defaults.UDPLossRate, "Fraction of UDP packets that are dropped")
+	flags.Float64Var(&c.UDPDuplicateRate, prefix+"udp.duplicate-rate", defaults.UDPDuplicateRate, "Fraction of UDP replies that are sent twice")
+	flags.Float64Var(&c.UDPReorderRate, prefix+"udp.reorder-rate", defaults.UDPReorderRate, "Fraction of UDP replies that are delayed by the reorder delay")
+	flags.DurationVar(&c.UDPReorderDelay, prefix+"udp.reorder-delay", defaults.UDPReorderDelay, "Delay of reordered UDP replies")
+	return &flags
+}
+
+// Faults returns the faults of the configuration.
+func (c FaultsConfig) Faults() (*echo.Faults, error) {
+	distribution, ok := echo.LatencyFault_Distribution_value[strings.ToUpper(c.LatencyDistribution)]
+	if !ok {
+		return nil, fmt.Errorf("unknown latency distribution %q", c.LatencyDistribution)
+	}
+	faults := &echo.Faults{
+		Latency: &echo.LatencyFault{
+			Distribution: echo.LatencyFault_Distribution(distribution),
+			Mean:         durationpb.New(c.LatencyMean),
+			Jitter:       durationpb.New(c.LatencyJitter),
+		},
+		Error: &echo.ErrorFault{
+			Rate:    c.ErrorRate,
+			Code:    c.ErrorCode,
+			Message: c.ErrorMessage,
+		},
+		Stream: &echo.StreamFault{
+			ResetRate: c.TCPResetRate,
+		},
+		Packet: &echo.PacketFault{
+			LossRate:      c.UDPLossRate,
+			DuplicateRate: c.UDPDuplicateRate,
+			ReorderRate:   c.UDPReorderRate,
+			ReorderDelay:  durationpb.New(c.UDPReorderDelay),
+		},
+	}
+	if err := faults.Validate(); err != nil {
+		return nil, err
+	}
+	return faults, nil
+}
+
+// defaultReorderDelay is the delay of reordered UDP replies if the faults do not set it.
+const defaultReorderDelay = 100 * time.Millisecond
+
+// faults holds the faults that are injected. The faults are replaced as a whole,
+// and are not modified after they are set.
+type faults struct {
+	mu     sync.RWMutex
+	faults *echo.Faults
+}
+
+func (f *faults) Get() *echo.Faults {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.faults
+}
+
+func (f *faults) Set(faults *echo.Faults) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = faults
+}
+
+// chance returns true with the probability of rate.
+func chance(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// latency returns a latency from the distribution of the fault.
+func latency(fault *echo.LatencyFault) time.Duration {
+	mean, jitter := fault.GetMean().AsDuration(), fault.GetJitter().AsDuration()
+	var d time.Duration
+	switch fault.GetDistribution() {
+	case echo.LatencyFault_UNIFORM:
+		d = mean - jitter + time.Duration(rand.Int63n(int64(2*jitter)+1))
+	case echo.LatencyFault_NORMAL:
+		d = mean + time.Duration(rand.NormFloat64()*float64(jitter))
+	case echo.LatencyFault_EXPONENTIAL:
+		d = time.Duration(rand.ExpFloat64() * float64(mean))
+	default:
+		d = mean
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// injectedError returns the error of the fault, if the call should fail.
+func injectedError(fault *echo.ErrorFault) error {
+	if !chance(fault.GetRate()) {
+		return nil
+	}
+	code, message := codes.Code(fault.GetCode()), fault.GetMessage()
+	if code == codes.OK {
+		code = codes.Unavailable
+	}
+	if message == "" {
+		message = "injected fault"
+	}
+	return status.Error(code, message)
+}
+
+var echoServicePrefix = "/" + echo.EchoService_ServiceDesc.ServiceName + "/"
+
+// inject injects the latency and errors into calls to the EchoService.
+func (f *faults) inject(ctx context.Context, fullMethod string) error {
+	if !strings.HasPrefix(fullMethod, echoServicePrefix) {
+		return nil
+	}
+	faults := f.Get()
+	if err := sleep(ctx, latency(faults.GetLatency())); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return injectedError(faults.GetError())
+}
+
+// UnaryServerInterceptor returns a unary server interceptor that injects faults.
+func (f *faults) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := f.inject(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a stream server interceptor that injects faults.
+func (f *faults) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := f.inject(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// resetConn closes the underlying TCP connection of conn with a reset instead of a FIN.
+func resetConn(conn net.Conn) error {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	if proxyConn, ok := conn.(*proxyproto.Conn); ok {
+		conn = proxyConn.Raw()
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		tcpConn.SetLinger(0)
+	}
+	return conn.Close()
+}
+
+// replyPacket sends the reply to a UDP packet, after injecting the packet faults.
+// Delayed replies are sent in the background, so that later replies can overtake them.
+func (es *EchoService) replyPacket(res []byte, reply func([]byte) error) error {
+	faults := es.faults.Get()
+	fault := faults.GetPacket()
+	if chance(fault.GetLossRate()) {
+		return nil
+	}
+	copies := 1
+	if chance(fault.GetDuplicateRate()) {
+		copies = 2
+	}
+	delay := latency(faults.GetLatency())
+	if chance(fault.GetReorderRate()) {
+		reorderDelay := fault.GetReorderDelay().AsDuration()
+		if reorderDelay <= 0 {
+			reorderDelay = defaultReorderDelay
+		}
+		delay += reorderDelay
+	}
+	send := func() error {
+		for i := 0; i < copies; i++ {
+			if err := reply(res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if delay <= 0 {
+		return send()
+	}
+	time.AfterFunc(delay, func() { send() })
+	return nil
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+)
+
+func TestLatency(t *testing.T) {
+	const (
+		mean    = 100 * time.Millisecond
+		jitter  = 20 * time.Millisecond
+		samples = 10000
+	)
+	for _, tc := range []struct {
+		distribution     echo.LatencyFault_Distribution
+		min, max         time.Duration
+		minMean, maxMean time.Duration
+	}{
+		{echo.LatencyFault_FIXED, mean, mean, mean, mean},
+		{echo.LatencyFault_UNIFORM, mean - jitter, mean + jitter, 95 * time.Millisecond, 105 * time.Millisecond},
+		{echo.LatencyFault_NORMAL, 0, mean + 10*jitter, 95 * time.Millisecond, 105 * time.Millisecond},
+		{echo.LatencyFault_EXPONENTIAL, 0, 100 * mean, 90 * time.Millisecond, 110 * time.Millisecond},
+	} {
+		t.Run(tc.distribution.String(), func(t *testing.T) {
+			fault := &echo.LatencyFault{
+				Distribution: tc.distribution,
+				Mean:         durationpb.New(mean),
+				Jitter:       durationpb.New(jitter),
+			}
+			var total time.Duration
+			for i := 0; i < samples; i++ {
+				d := latency(fault)
+				if d < tc.min || d > tc.max {
+					t.Fatalf("latency %s is outside [%s, %s]", d, tc.min, tc.max)
+				}
+				total += d
+			}
+			if avg := total / samples; avg < tc.minMean || avg > tc.maxMean {
+				t.Errorf("average latency %s is outside [%s, %s]", avg, tc.minMean, tc.maxMean)
+			}
+		})
+	}
+
+	if d := latency(&echo.LatencyFault{
+		Distribution: echo.LatencyFault_NORMAL,
+		Jitter:       durationpb.New(time.Second),
+	}); d < 0 {
+		t.Errorf("negative latency %s", d)
+	}
+	if d := latency(nil); d != 0 {
+		t.Errorf("latency without fault is %s, want 0", d)
+	}
+}
+
+func TestFaultsConfig(t *testing.T) {
+	faults, err := DefaultFaultsConfig().Faults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := latency(faults.GetLatency()); d != 0 {
+		t.Errorf("default latency is %s, want 0", d)
+	}
+	if err := injectedError(faults.GetError()); err != nil {
+		t.Errorf("default faults inject error %v", err)
+	}
+
+	for _, modify := range []func(*FaultsConfig){
+		func(c *FaultsConfig) { c.LatencyDistribution = "poisson" },
+		func(c *FaultsConfig) { c.LatencyMean = -time.Second },
+		func(c *FaultsConfig) { c.ErrorRate = 1.5 },
+		func(c *FaultsConfig) { c.ErrorCode = 17 },
+		func(c *FaultsConfig) { c.UDPLossRate = -0.1 },
+	} {
+		config := DefaultFaultsConfig()
+		modify(config)
+		if _, err := config.Faults(); err == nil {
+			t.Errorf("no error for invalid config %+v", config)
+		}
+	}
+}
+
+func TestSetFaults(t *testing.T) {
+	initial, err := DefaultFaultsConfig().Faults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f faults
+	f.Set(initial)
+	as := &adminService{faults: &f}
+	ctx := context.Background()
+
+	for _, req := range []*echo.SetFaultsRequest{
+		{},
+		{Faults: &echo.Faults{Latency: &echo.LatencyFault{Mean: durationpb.New(2 * time.Minute)}}},
+		{Faults: &echo.Faults{Latency: &echo.LatencyFault{Distribution: 42}}},
+		{Faults: &echo.Faults{Error: &echo.ErrorFault{Rate: 2}}},
+		{Faults: &echo.Faults{Error: &echo.ErrorFault{Code: 100}}},
+		{Faults: &echo.Faults{Stream: &echo.StreamFault{ResetRate: -1}}},
+		{Faults: &echo.Faults{Packet: &echo.PacketFault{ReorderRate: 1.1}}},
+		{Faults: &echo.Faults{Packet: &echo.PacketFault{ReorderDelay: durationpb.New(-time.Second)}}},
+	} {
+		if _, err := as.SetFaults(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("SetFaults(%v) returned %v, want InvalidArgument", req, err)
+		}
+		if f.Get() != initial {
+			t.Fatalf("SetFaults(%v) changed the faults", req)
+		}
+	}
+
+	updated := &echo.Faults{Error: &echo.ErrorFault{Rate: 1, Code: uint32(codes.Internal)}}
+	if _, err := as.SetFaults(ctx, &echo.SetFaultsRequest{Faults: updated}); err != nil {
+		t.Fatal(err)
+	}
+	current, err := as.GetFaults(ctx, &echo.GetFaultsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(current, updated) {
+		t.Errorf("faults are %v, want %v", current, updated)
+	}
+	if err := f.inject(ctx, echoServicePrefix+"Echo"); status.Code(err) != codes.Internal {
+		t.Errorf("inject returned %v, want Internal", err)
+	}
+	if err := f.inject(ctx, "/grpc.health.v1.Health/Check"); err != nil {
+		t.Errorf("inject into other service returned %v", err)
+	}
+}
+
+func TestHandlePacket(t *testing.T) {
+	for prefix, expected := range map[string]string{
+		"":         "hello",
+		"<echo>: ": "<echo>: hello",
+	} {
+		es, err := NewEchoService(Config{Faults: *DefaultFaultsConfig(), Prefix: prefix})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var replies []string
+		if err := es.HandlePacket(context.Background(), []byte("hello"), nil, func(res []byte) error {
+			replies = append(replies, string(res))
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if len(replies) != 1 || replies[0] != expected {
+			t.Errorf("replies with prefix %q are %q, want %q", prefix, replies, expected)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+)
+
+var whoamiMarshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// isForwarded returns whether a header or metadata key is added by proxies
+// or by the gRPC gateway.
+func isForwarded(key string) bool {
+	switch {
+	case strings.HasPrefix(key, "x-forwarded-"), strings.HasPrefix(key, "grpcgateway-"):
+		return true
+	case key == "forwarded", key == "x-real-ip", key == "via":
+		return true
+	default:
+		return false
+	}
+}
+
+func newWhoamiResponse(protocol string, receivedAt time.Time) *echo.WhoamiResponse {
+	hostname, _ := os.Hostname()
+	return &echo.WhoamiResponse{
+		Protocol:   protocol,
+		Hostname:   hostname,
+		ReceivedAt: timestamppb.New(receivedAt),
+	}
+}
+
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
+
+// setHeaders sets the metadata and forwarded fields from headers or metadata.
+func setHeaders(res *echo.WhoamiResponse, headers map[string][]string) {
+	for key, values := range headers {
+		key = strings.ToLower(key)
+		target := &res.Metadata
+		if isForwarded(key) {
+			target = &res.Forwarded
+		}
+		if *target == nil {
+			*target = make(map[string]*echo.Values)
+		}
+		if existing, ok := (*target)[key]; ok {
+			existing.Values = append(existing.Values, values...)
+			continue
+		}
+		(*target)[key] = &echo.Values{Values: append([]string(nil), values...)}
+	}
+}
+
+func newTLSInfo(state *tls.ConnectionState) *echo.TLSInfo {
+	if state == nil {
+		return nil
+	}
+	info := &echo.TLSInfo{
+		Version:            tlsVersionName(state.Version),
+		CipherSuite:        tls.CipherSuiteName(state.CipherSuite),
+		ServerName:         state.ServerName,
+		NegotiatedProtocol: state.NegotiatedProtocol,
+	}
+	for _, cert := range state.PeerCertificates {
+		info.PeerCertificates = append(info.PeerCertificates, cert.Subject.String())
+	}
+	return info
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return "unknown"
+	}
+}
+
+// proxyAddr returns the address of the proxy that sent the PROXY protocol header
+// on conn, or nil if there was no header.
+func proxyAddr(conn net.Conn) net.Addr {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	proxyConn, ok := conn.(*proxyproto.Conn)
+	if !ok || proxyConn.ProxyHeader() == nil {
+		return nil
+	}
+	return proxyConn.Raw().RemoteAddr()
+}
+
+func (es *EchoService) Whoami(ctx context.Context, _ *echo.WhoamiRequest) (*echo.WhoamiResponse, error) {
+	res := newWhoamiResponse("grpc", time.Now())
+	if p, ok := peer.FromContext(ctx); ok {
+		res.PeerAddress, res.LocalAddress = addrString(p.Addr), addrString(p.LocalAddr)
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			res.Tls = newTLSInfo(&tlsInfo.State)
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	setHeaders(res, md)
+	if deadline, ok := ctx.Deadline(); ok {
+		res.Timeout = durationpb.New(time.Until(deadline))
+	}
+	return res, nil
+}
+
+// HandleWhoamiStream writes the whoami response of a TCP connection and closes it.
+// If the PROXY protocol is enabled, the response is only written after the client
+// sent the PROXY header or other data.
+func (es *EchoService) HandleWhoamiStream(ctx context.Context, conn net.Conn) error {
+	receivedAt := time.Now()
+	conn.SetDeadline(receivedAt.Add(es.config.TCPTimeout))
+	res := newWhoamiResponse("tcp", receivedAt)
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return err
+		}
+		state := tlsConn.ConnectionState()
+		res.Protocol, res.Tls = "tls", newTLSInfo(&state)
+	}
+	res.PeerAddress, res.LocalAddress = addrString(conn.RemoteAddr()), addrString(conn.LocalAddr())
+	res.ProxyAddress = addrString(proxyAddr(conn))
+	b, err := whoamiMarshalOptions.Marshal(res)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(append(b, '\n'))
+	return err
+}
+
+// HandleWhoamiPacket replies to a UDP packet with the whoami response.
+func (es *EchoService) HandleWhoamiPacket(ctx context.Context, msg []byte, addr net.Addr, reply func([]byte) error) error {
+	res := newWhoamiResponse("udp", time.Now())
+	res.PeerAddress = addrString(addr)
+	b, err := whoamiMarshalOptions.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return reply(append(b, '\n'))
+}
+
+// HandleWhoamiHTTP responds with the whoami response of the HTTP request.
+func (es *EchoService) HandleWhoamiHTTP(w http.ResponseWriter, r *http.Request) {
+	res := newWhoamiResponse("http", time.Now())
+	res.PeerAddress = r.RemoteAddr
+	if localAddr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		res.LocalAddress = localAddr.String()
+	}
+	res.Tls = newTLSInfo(r.TLS)
+	headers := r.Header.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	headers.Set("Host", r.Host)
+	setHeaders(res, headers)
+	b, err := whoamiMarshalOptions.Marshal(res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(append(b, '\n'))
+}
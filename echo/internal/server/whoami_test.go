@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	echo "htdvisser.dev/exp/echo/api/v1alpha1"
+)
+
+func keys(values map[string]*echo.Values) []string {
+	out := make([]string, 0, len(values))
+	for key := range values {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestWhoamiHTTP(t *testing.T) {
+	es, err := NewEchoService(*DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://echo.example.com/whoami", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Request-Id", "abc")
+	req.Header.Add("X-Forwarded-For", "198.51.100.1")
+	req.Header.Add("X-Forwarded-For", "198.51.100.2")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("Forwarded", "for=198.51.100.1")
+	req.Header.Set("X-Real-Ip", "198.51.100.1")
+	req.Header.Set("Via", "1.1 proxy")
+	rec := httptest.NewRecorder()
+	es.HandleWhoamiHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status is %d, want %d", rec.Code, http.StatusOK)
+	}
+	var res echo.WhoamiResponse
+	if err := protojson.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.GetProtocol() != "http" || res.GetPeerAddress() != "192.0.2.1:1234" {
+		t.Errorf("unexpected protocol %q and peer address %q", res.GetProtocol(), res.GetPeerAddress())
+	}
+	if expected := []string{"forwarded", "via", "x-forwarded-for", "x-forwarded-proto", "x-real-ip"}; !reflect.DeepEqual(keys(res.GetForwarded()), expected) {
+		t.Errorf("forwarded headers are %v, want %v", keys(res.GetForwarded()), expected)
+	}
+	if expected := []string{"accept", "host", "x-request-id"}; !reflect.DeepEqual(keys(res.GetMetadata()), expected) {
+		t.Errorf("metadata is %v, want %v", keys(res.GetMetadata()), expected)
+	}
+	if values := res.GetForwarded()["x-forwarded-for"].GetValues(); !reflect.DeepEqual(values, []string{"198.51.100.1", "198.51.100.2"}) {
+		t.Errorf("x-forwarded-for is %v", values)
+	}
+	if values := res.GetMetadata()["host"].GetValues(); !reflect.DeepEqual(values, []string{"echo.example.com"}) {
+		t.Errorf("host is %v", values)
+	}
+}
+
+func TestWhoami(t *testing.T) {
+	es, err := NewEchoService(*DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer token",
+		"grpcgateway-user-agent", "curl",
+		"x-forwarded-host", "echo.example.com",
+	))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	res, err := es.Whoami(ctx, &echo.WhoamiRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"grpcgateway-user-agent", "x-forwarded-host"}; !reflect.DeepEqual(keys(res.GetForwarded()), expected) {
+		t.Errorf("forwarded metadata is %v, want %v", keys(res.GetForwarded()), expected)
+	}
+	if expected := []string{"authorization"}; !reflect.DeepEqual(keys(res.GetMetadata()), expected) {
+		t.Errorf("metadata is %v, want %v", keys(res.GetMetadata()), expected)
+	}
+	if timeout := res.GetTimeout().AsDuration(); timeout <= 0 || timeout > time.Minute {
+		t.Errorf("timeout is %s, want at most 1m", timeout)
+	}
+}
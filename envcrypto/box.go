@@ -5,9 +5,13 @@ import (
 	"encoding/base32"
 	"fmt"
 	"io"
+	"regexp"
+	"sort"
 	"strings"
 
+	"golang.org/x/crypto/curve25519"
 	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
 )
 
 type Encoding interface {
@@ -15,6 +19,19 @@ type Encoding interface {
 	DecodeString(string) ([]byte, error)
 }
 
+// Box encrypts and decrypts values with the keys in a Source.
+//
+// Values are anonymously sealed to the public key under the public key key
+// (ENVCRYPTO_PUBLIC_KEY), or encrypted to recipients. Recipients have a key ID,
+// and their keys are stored under the public and private key keys, suffixed
+// with "_" and the key ID (such as ENVCRYPTO_PUBLIC_KEY_OPS). Values that are
+// encrypted to recipients have the format
+//
+//	!envcrypto:<id>=<sealed key>,<id>=<sealed key>:<sealed value>
+//
+// where the sealed value is a nonce followed by the value, sealed with a random
+// key using secretbox, and each sealed key is that random key, anonymously sealed
+// to the public key of the recipient.
 type Box struct {
 	source Source
 
@@ -24,16 +41,29 @@ type Box struct {
 	publicKeyKey             string
 	privateKeyKey            string
 	encryptedDataValuePrefix string
+	recipients               []string
 
 	// state
 	publicKeyString  string
 	privateKeyString string
 	publicKey        *[32]byte
 	privateKey       *[32]byte
+	publicKeys       map[string]*[32]byte
+	privateKeys      map[string]*[32]byte
 }
 
 const sopsEncryptedPrefix = "ENC["
 
+// keyIDPattern matches the key IDs of recipients.
+var keyIDPattern = regexp.MustCompile(`^[A-Z0-9_]+$`)
+
+func validateKeyID(id string) error {
+	if !keyIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid key ID %q", id)
+	}
+	return nil
+}
+
 func newBox(source Source, options ...Option) *Box {
 	b := &Box{
 		source: source,
@@ -65,6 +95,22 @@ func New(options ...Option) (MapSource, error) {
 	return s, nil
 }
 
+func NewRecipient(id string, options ...Option) (MapSource, error) {
+	id = strings.ToUpper(id)
+	if err := validateKeyID(id); err != nil {
+		return nil, err
+	}
+	s := make(MapSource)
+	b := newBox(s, options...)
+	publicKey, privateKey, err := box.GenerateKey(b.rand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+	s[b.publicKeyKey+"_"+id] = b.encoding.EncodeToString(publicKey[:])
+	s[b.privateKeyKey+"_"+id] = b.encoding.EncodeToString(privateKey[:])
+	return s, nil
+}
+
 func (b *Box) parseKey(s string) (*[32]byte, error) {
 	key, err := b.encoding.DecodeString(s)
 	if err != nil {
@@ -78,20 +124,85 @@ func (b *Box) parseKey(s string) (*[32]byte, error) {
 	return &key32, nil
 }
 
+// recipientKeyID returns the key ID of a recipient key, if key is one.
+func (b *Box) recipientKeyID(key string) (id string, public, ok bool) {
+	if id, ok := strings.CutPrefix(key, b.publicKeyKey+"_"); ok && keyIDPattern.MatchString(id) {
+		return id, true, true
+	}
+	if id, ok := strings.CutPrefix(key, b.privateKeyKey+"_"); ok && keyIDPattern.MatchString(id) {
+		return id, false, true
+	}
+	return "", false, false
+}
+
+func (b *Box) isKey(key string) bool {
+	if key == b.publicKeyKey || key == b.privateKeyKey {
+		return true
+	}
+	_, _, ok := b.recipientKeyID(key)
+	return ok
+}
+
+func (b *Box) openRecipients() error {
+	b.publicKeys, b.privateKeys = make(map[string]*[32]byte), make(map[string]*[32]byte)
+	for _, key := range b.source.Keys() {
+		id, public, ok := b.recipientKeyID(key)
+		if !ok {
+			continue
+		}
+		value, _ := b.source.Lookup(key)
+		if value == "" || strings.HasPrefix(value, sopsEncryptedPrefix) {
+			continue
+		}
+		parsed, err := b.parseKey(value)
+		if err != nil {
+			if public {
+				return fmt.Errorf("failed to parse public key %q of %s: %w", value, id, err)
+			}
+			return fmt.Errorf("failed to parse private key of %s: %w", id, err)
+		}
+		if public {
+			b.publicKeys[id] = parsed
+		} else {
+			b.privateKeys[id] = parsed
+		}
+	}
+	// The public key of a recipient can be derived from its private key.
+	for id, privateKey := range b.privateKeys {
+		if _, ok := b.publicKeys[id]; ok {
+			continue
+		}
+		publicKey, err := curve25519.X25519(privateKey[:], curve25519.Basepoint)
+		if err != nil {
+			return fmt.Errorf("failed to derive public key of %s: %w", id, err)
+		}
+		var publicKey32 [32]byte
+		copy(publicKey32[:], publicKey)
+		b.publicKeys[id] = &publicKey32
+	}
+	return nil
+}
+
 func Open(source Source, options ...Option) (*Box, error) {
 	b := newBox(source, options...)
 
+	if err := b.openRecipients(); err != nil {
+		return nil, err
+	}
+
 	if b.publicKeyString == "" {
 		b.publicKeyString, _ = source.Lookup(b.publicKeyKey)
 	}
-	if b.publicKeyString == "" {
+	if b.publicKeyString == "" && len(b.publicKeys) == 0 {
 		return nil, fmt.Errorf("no public key")
 	}
-	publicKey, err := b.parseKey(b.publicKeyString)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse public key %q: %w", b.publicKeyString, err)
+	if b.publicKeyString != "" {
+		publicKey, err := b.parseKey(b.publicKeyString)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %q: %w", b.publicKeyString, err)
+		}
+		b.publicKey = publicKey
 	}
-	b.publicKey = publicKey
 
 	if b.privateKeyString == "" {
 		b.privateKeyString, _ = source.Lookup(b.privateKeyKey)
@@ -107,7 +218,28 @@ func Open(source Source, options ...Option) (*Box, error) {
 	return b, nil
 }
 
+func (b *Box) Recipients() []string {
+	ids := make([]string, 0, len(b.publicKeys))
+	for id := range b.publicKeys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Encrypt encrypts the value to the recipients of the WithRecipients option.
+// Without that option, the value is sealed to the public key without key ID if
+// there is one, or encrypted to all recipients otherwise.
 func (b *Box) Encrypt(value string) (string, error) {
+	if len(b.recipients) > 0 {
+		return b.encryptToRecipients(value, b.recipients)
+	}
+	if b.publicKey == nil {
+		if recipients := b.Recipients(); len(recipients) > 0 {
+			return b.encryptToRecipients(value, recipients)
+		}
+		return "", fmt.Errorf("no public key")
+	}
 	out, err := box.SealAnonymous(nil, []byte(value), b.publicKey, b.rand)
 	if err != nil {
 		return "", err
@@ -116,8 +248,108 @@ func (b *Box) Encrypt(value string) (string, error) {
 	return b.encryptedDataValuePrefix + encryptedValue, nil
 }
 
+func (b *Box) encryptToRecipients(value string, recipients []string) (string, error) {
+	var (
+		key   [32]byte
+		nonce [24]byte
+	)
+	if _, err := io.ReadFull(b.rand, key[:]); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	if _, err := io.ReadFull(b.rand, nonce[:]); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealedKeys := make([]string, len(recipients))
+	for i, id := range recipients {
+		id = strings.ToUpper(id)
+		publicKey, ok := b.publicKeys[id]
+		if !ok {
+			return "", fmt.Errorf("no public key for recipient %q", id)
+		}
+		sealedKey, err := box.SealAnonymous(nil, key[:], publicKey, b.rand)
+		if err != nil {
+			return "", err
+		}
+		sealedKeys[i] = id + "=" + b.encoding.EncodeToString(sealedKey)
+	}
+	out := secretbox.Seal(nonce[:], []byte(value), &nonce, &key)
+	return b.encryptedDataValuePrefix + strings.Join(sealedKeys, ",") + ":" + b.encoding.EncodeToString(out), nil
+}
+
+// parseRecipients parses the sealed keys of a value that is encrypted to
+// recipients, and returns the sealed keys by key ID, in the order of the value.
+func (b *Box) parseRecipients(sealedKeys string) (ids []string, keys map[string][]byte, err error) {
+	keys = make(map[string][]byte)
+	for _, sealedKey := range strings.Split(sealedKeys, ",") {
+		id, encodedKey, ok := strings.Cut(sealedKey, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid sealed key")
+		}
+		if err := validateKeyID(id); err != nil {
+			return nil, nil, err
+		}
+		key, err := b.encoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode sealed key of %s: %w", id, err)
+		}
+		ids, keys[id] = append(ids, id), key
+	}
+	return ids, keys, nil
+}
+
+// ValueRecipients returns the key IDs of the recipients that an encrypted value
+// was encrypted to. It returns nil for values that are not encrypted to recipients.
+func (b *Box) ValueRecipients(encryptedValue string) ([]string, error) {
+	encryptedValue = strings.TrimPrefix(encryptedValue, b.encryptedDataValuePrefix)
+	sealedKeys, _, ok := strings.Cut(encryptedValue, ":")
+	if !ok {
+		return nil, nil
+	}
+	ids, _, err := b.parseRecipients(sealedKeys)
+	return ids, err
+}
+
+func (b *Box) decryptFromRecipients(sealedKeys, encryptedValue string) (string, error) {
+	ids, keys, err := b.parseRecipients(sealedKeys)
+	if err != nil {
+		return "", err
+	}
+	encryptedValueBytes, err := b.encoding.DecodeString(encryptedValue)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+	if len(encryptedValueBytes) < 24 {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+	for _, id := range ids {
+		privateKey, ok := b.privateKeys[id]
+		if !ok {
+			continue
+		}
+		key, ok := box.OpenAnonymous(nil, keys[id], b.publicKeys[id], privateKey)
+		if !ok || len(key) != 32 {
+			return "", fmt.Errorf("failed to decrypt key of %s", id)
+		}
+		var (
+			key32 [32]byte
+			nonce [24]byte
+		)
+		copy(key32[:], key)
+		copy(nonce[:], encryptedValueBytes[:24])
+		out, ok := secretbox.Open(nil, encryptedValueBytes[24:], &nonce, &key32)
+		if !ok {
+			return "", fmt.Errorf("failed to decrypt value")
+		}
+		return string(out), nil
+	}
+	return "", fmt.Errorf("no private key for recipients %s", strings.Join(ids, ", "))
+}
+
 func (b *Box) Decrypt(encryptedValue string) (string, error) {
 	encryptedValue = strings.TrimPrefix(encryptedValue, b.encryptedDataValuePrefix)
+	if sealedKeys, encryptedValue, ok := strings.Cut(encryptedValue, ":"); ok {
+		return b.decryptFromRecipients(sealedKeys, encryptedValue)
+	}
 	encryptedValueBytes, err := b.encoding.DecodeString(encryptedValue)
 	if err != nil {
 		return "", fmt.Errorf("failed to decode encrypted value: %w", err)
@@ -148,7 +380,7 @@ func (b *Box) Get(key string) (string, error) {
 func (b *Box) All() (map[string]string, error) {
 	m := make(map[string]string)
 	for _, key := range b.source.Keys() {
-		if key == b.publicKeyKey || key == b.privateKeyKey {
+		if b.isKey(key) {
 			continue
 		}
 		value, err := b.Get(key)
@@ -1,7 +1,10 @@
 package envcrypto
 
 import (
+	"strings"
 	"testing"
+
+	"golang.org/x/exp/slices"
 )
 
 func TestNew(t *testing.T) {
@@ -193,3 +196,183 @@ func TestBox_All(t *testing.T) {
 		t.Errorf("All() returned wrong value for BAR: %q, want %q", all["BAR"], "bar")
 	}
 }
+
+func newRecipients(t *testing.T, ids ...string) MapSource {
+	t.Helper()
+
+	m := make(MapSource)
+	for _, id := range ids {
+		recipient, err := NewRecipient(id)
+		if err != nil {
+			t.Fatalf("NewRecipient(%q) returned error: %v", id, err)
+		}
+		for k, v := range recipient {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// withoutPrivateKeys returns a copy of m without the private keys of recipients
+// other than the given recipient.
+func withoutPrivateKeys(m MapSource, except string) MapSource {
+	out := make(MapSource)
+	for k, v := range m {
+		if strings.HasPrefix(k, "ENVCRYPTO_PRIVATE_KEY_") && k != "ENVCRYPTO_PRIVATE_KEY_"+except {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func TestNewRecipient(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewRecipient("ops")
+	if err != nil {
+		t.Fatalf("NewRecipient() returned error: %v", err)
+	}
+	if _, ok := m["ENVCRYPTO_PUBLIC_KEY_OPS"]; !ok {
+		t.Error("NewRecipient() did not set ENVCRYPTO_PUBLIC_KEY_OPS")
+	}
+	if _, ok := m["ENVCRYPTO_PRIVATE_KEY_OPS"]; !ok {
+		t.Error("NewRecipient() did not set ENVCRYPTO_PRIVATE_KEY_OPS")
+	}
+
+	if _, err := NewRecipient("not ok"); err == nil {
+		t.Error("NewRecipient() did not return error for invalid key ID")
+	}
+}
+
+func TestBox_Encrypt_recipients(t *testing.T) {
+	t.Parallel()
+
+	m := newRecipients(t, "OPS", "CI", "PROD")
+	box, _ := Open(m)
+
+	encryptedValue, err := box.Encrypt("foo")
+	if err != nil {
+		t.Fatalf("Encrypt() returned error: %v", err)
+	}
+
+	recipients, err := box.ValueRecipients(encryptedValue)
+	if err != nil {
+		t.Fatalf("ValueRecipients() returned error: %v", err)
+	}
+	if !slices.Equal(recipients, []string{"CI", "OPS", "PROD"}) {
+		t.Errorf("ValueRecipients() returned %v, want [CI OPS PROD]", recipients)
+	}
+
+	for _, id := range recipients {
+		box, err := Open(withoutPrivateKeys(m, id))
+		if err != nil {
+			t.Fatalf("Open() returned error: %v", err)
+		}
+		decryptedValue, err := box.Decrypt(encryptedValue)
+		if err != nil {
+			t.Fatalf("Decrypt() as %s returned error: %v", id, err)
+		}
+		if decryptedValue != "foo" {
+			t.Errorf("Decrypt() as %s returned %q, want %q", id, decryptedValue, "foo")
+		}
+	}
+}
+
+func TestBox_Encrypt_withRecipients(t *testing.T) {
+	t.Parallel()
+
+	m := newRecipients(t, "OPS", "CI", "PROD")
+	box, _ := Open(m, WithRecipients("ops", "ci"))
+
+	encryptedValue, err := box.Encrypt("foo")
+	if err != nil {
+		t.Fatalf("Encrypt() returned error: %v", err)
+	}
+
+	ciBox, _ := Open(withoutPrivateKeys(m, "CI"))
+	if decryptedValue, err := ciBox.Decrypt(encryptedValue); err != nil || decryptedValue != "foo" {
+		t.Errorf("Decrypt() as CI returned %q, %v, want %q", decryptedValue, err, "foo")
+	}
+
+	prodBox, _ := Open(withoutPrivateKeys(m, "PROD"))
+	if _, err := prodBox.Decrypt(encryptedValue); err == nil {
+		t.Error("Decrypt() as PROD did not return error")
+	}
+
+	box, _ = Open(m, WithRecipients("dev"))
+	if _, err := box.Encrypt("foo"); err == nil {
+		t.Error("Encrypt() did not return error for unknown recipient")
+	}
+}
+
+func TestBox_Encrypt_publicKeyAndRecipients(t *testing.T) {
+	t.Parallel()
+
+	m, _ := New()
+	for k, v := range newRecipients(t, "OPS") {
+		m[k] = v
+	}
+	box, _ := Open(m)
+
+	encryptedValue, err := box.Encrypt("foo")
+	if err != nil {
+		t.Fatalf("Encrypt() returned error: %v", err)
+	}
+	if recipients, _ := box.ValueRecipients(encryptedValue); recipients != nil {
+		t.Errorf("Encrypt() encrypted to recipients %v, want the public key without key ID", recipients)
+	}
+	delete(m, "ENVCRYPTO_PRIVATE_KEY_OPS")
+	box, _ = Open(m)
+	if decryptedValue, err := box.Decrypt(encryptedValue); err != nil || decryptedValue != "foo" {
+		t.Errorf("Decrypt() returned %q, %v", decryptedValue, err)
+	}
+
+	box, _ = Open(m, WithRecipients("ops"))
+	encryptedValue, _ = box.Encrypt("foo")
+	if recipients, _ := box.ValueRecipients(encryptedValue); !slices.Equal(recipients, []string{"OPS"}) {
+		t.Errorf("ValueRecipients() returned %v, want [OPS]", recipients)
+	}
+}
+
+func TestBox_Decrypt_withoutRecipients(t *testing.T) {
+	t.Parallel()
+
+	m, _ := New()
+	box, _ := Open(m)
+	encryptedValue, _ := box.Encrypt("foo")
+
+	for k, v := range newRecipients(t, "OPS") {
+		m[k] = v
+	}
+	box, _ = Open(m)
+
+	decryptedValue, err := box.Decrypt(encryptedValue)
+	if err != nil {
+		t.Fatalf("Decrypt() returned error: %v", err)
+	}
+	if decryptedValue != "foo" {
+		t.Error("Decrypt() returned wrong value")
+	}
+
+	if _, err := box.Decrypt(strings.Replace(encryptedValue, "!envcrypto:", "!envcrypto:OPS=AAAA:", 1)); err == nil {
+		t.Error("Decrypt() did not return error for invalid sealed key")
+	}
+}
+
+func TestBox_All_recipients(t *testing.T) {
+	t.Parallel()
+
+	m := newRecipients(t, "OPS", "CI")
+	box, _ := Open(m)
+
+	m["FOO"], _ = box.Encrypt("foo")
+
+	all, err := box.All()
+	if err != nil {
+		t.Fatalf("All() returned error: %v", err)
+	}
+	if len(all) != 1 || all["FOO"] != "foo" {
+		t.Errorf("All() returned %v, want only FOO", all)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
@@ -28,7 +29,8 @@ type App struct {
 
 	envFilesSource *envcrypto.EnvFilesSource
 
-	box *envcrypto.Box
+	boxOptions []envcrypto.Option
+	box        *envcrypto.Box
 }
 
 func (app *App) writeString(s string) {
@@ -96,11 +98,22 @@ func (app *App) loadSOPS(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+func (app *App) loadRecipients(cmd *cobra.Command, _ []string) error {
+	recipients, err := cmd.Flags().GetStringSlice("recipients")
+	if err != nil {
+		return err
+	}
+	if len(recipients) > 0 {
+		app.boxOptions = append(app.boxOptions, envcrypto.WithRecipients(recipients...))
+	}
+	return nil
+}
+
 func (app *App) openBox(_ *cobra.Command, _ []string) error {
 	box, err := envcrypto.Open(envcrypto.MultiSource{
 		envcrypto.EnvSource{},
 		app.envFilesSource,
-	})
+	}, app.boxOptions...)
 	if err != nil {
 		return err
 	}
@@ -133,6 +146,7 @@ func (app *App) rootCmd() *cobra.Command {
 	cmd.AddCommand(app.encryptCmd())
 	cmd.AddCommand(app.decryptCmd())
 	cmd.AddCommand(app.getCmd())
+	cmd.AddCommand(app.recipientsCmd())
 
 	return cmd
 }
@@ -144,8 +158,17 @@ func (app *App) generateCmd() *cobra.Command {
 		PreRunE: multiRunE(
 			app.loadConfig,
 		),
-		RunE: func(_ *cobra.Command, _ []string) error {
-			m, err := envcrypto.New()
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			recipient, err := cmd.Flags().GetString("recipient")
+			if err != nil {
+				return err
+			}
+			var m envcrypto.MapSource
+			if recipient != "" {
+				m, err = envcrypto.NewRecipient(recipient)
+			} else {
+				m, err = envcrypto.New()
+			}
 			if err != nil {
 				return err
 			}
@@ -157,6 +180,7 @@ func (app *App) generateCmd() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().String("recipient", "", "generate the keys of the recipient with this key ID")
 	return cmd
 }
 
@@ -169,6 +193,7 @@ func (app *App) encryptCmd() *cobra.Command {
 			app.loadConfig,
 			app.loadFiles,
 			app.loadSOPS,
+			app.loadRecipients,
 			app.openBox,
 		),
 		RunE: func(_ *cobra.Command, args []string) error {
@@ -182,6 +207,7 @@ func (app *App) encryptCmd() *cobra.Command {
 	}
 	cmd.Flags().StringSliceP("files", "f", nil, "files to read environment variables from")
 	cmd.Flags().String("sops", "", "decrypt environment file with sops")
+	cmd.Flags().StringSliceP("recipients", "r", nil, "key IDs of the recipients to encrypt to (default the public key without key ID, or all recipients if there is none)")
 	return cmd
 }
 
@@ -232,3 +258,32 @@ func (app *App) getCmd() *cobra.Command {
 	cmd.Flags().String("sops", "", "decrypt environment file with sops")
 	return cmd
 }
+
+func (app *App) recipientsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipients [value]",
+		Short: "list the recipients of an encrypted value, or the recipients with a public key",
+		Args:  cobra.MaximumNArgs(1),
+		PreRunE: multiRunE(
+			app.loadConfig,
+			app.loadFiles,
+			app.loadSOPS,
+			app.openBox,
+		),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				app.writeString(strings.Join(app.box.Recipients(), "\n"))
+				return nil
+			}
+			recipients, err := app.box.ValueRecipients(args[0])
+			if err != nil {
+				return err
+			}
+			app.writeString(strings.Join(recipients, "\n"))
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceP("files", "f", nil, "files to read environment variables from")
+	cmd.Flags().String("sops", "", "decrypt environment file with sops")
+	return cmd
+}
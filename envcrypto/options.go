@@ -9,3 +9,10 @@ type OptionFunc func(*Box) error
 func (f OptionFunc) applyToBox(b *Box) error {
 	return f(b)
 }
+
+func WithRecipients(ids ...string) Option {
+	return OptionFunc(func(b *Box) error {
+		b.recipients = ids
+		return nil
+	})
+}
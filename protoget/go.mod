@@ -2,7 +2,26 @@ module htdvisser.dev/exp/protoget
 
 go 1.20
 
+replace htdvisser.dev/exp/backbone => ../backbone
+
+replace htdvisser.dev/exp/fieldpath => ../fieldpath
+
+replace htdvisser.dev/exp/tlsconfig => ../tlsconfig
+
+replace htdvisser.dev/exp/watcher => ../watcher
+
 require (
+	htdvisser.dev/exp/backbone v0.0.0-20231206185358-cf15410f4841
 	htdvisser.dev/exp/clicontext v1.1.0
 	htdvisser.dev/exp/flagenv v1.0.0
 )
+
+require (
+	github.com/benbjohnson/clock v1.3.5 // indirect
+	github.com/go-logr/logr v1.3.0 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+	go.opentelemetry.io/otel v1.21.0 // indirect
+	go.opentelemetry.io/otel/metric v1.21.0 // indirect
+	go.opentelemetry.io/otel/trace v1.21.0 // indirect
+)
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	bbhttp "htdvisser.dev/exp/backbone/client/http"
 	"htdvisser.dev/exp/clicontext"
 	"htdvisser.dev/exp/flagenv"
 )
@@ -60,6 +61,14 @@ func main() {
 		app.config.Prefix += "/"
 	}
 
+	middleware := []bbhttp.Middleware{bbhttp.Retry(nil)}
+	if app.config.Debug {
+		middleware = append(middleware, bbhttp.Logging(nil))
+	}
+	app.client = &http.Client{
+		Transport: bbhttp.Chain(http.DefaultTransport, middleware...),
+	}
+
 	if err := app.Run(ctx, flag.Args()...); err != nil {
 		fmt.Fprintln(flag.CommandLine.Output(), err)
 		return
@@ -95,7 +104,7 @@ func (app *App) url(arg string) string {
 func (app *App) Get(ctx context.Context, arg string) (err error) {
 	url := app.url(arg)
 	log.Printf("Getting %q from %q", arg, url)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return err
 	}
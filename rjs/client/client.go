@@ -19,6 +19,10 @@ import (
 
 // New returns a new RJS Client on top of the given HTTP client.
 // It prepends the given base URL to API URIs, and sets the given Authorization header if not empty.
+// Retries, circuit breaking, logging and tracing can be added by passing an HTTP client with
+// middleware, such as the one returned by the Client func of the htdvisser.dev/exp/backbone/client/http
+// package. Keep in mind that RJS API calls are POST requests, which are not retried unless they are
+// marked as idempotent.
 func New(client *http.Client, baseURL, authorization string) (*Client, error) {
 	base, err := url.Parse(baseURL)
 	if err != nil {